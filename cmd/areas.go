@@ -0,0 +1,71 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"hhparser/internal/areas"
+	"hhparser/internal/config"
+	"hhparser/internal/logging"
+	"log/slog"
+	"os"
+	"text/tabwriter"
+)
+
+// listAreas выводит регионы справочника hh.ru, подходящие под --search
+func listAreas(args []string) {
+	flags := flag.NewFlagSet("list-areas", flag.ExitOnError)
+	search := flags.String("search", "", "часть названия города или региона")
+	_ = flags.Parse(args)
+
+	cfg, err := config.Load()
+	if err != nil {
+		fatal(err)
+	}
+	if err := logging.Setup(cfg.Log); err != nil {
+		fatal(err)
+	}
+
+	tree, err := areas.Load(areasOptions(cfg))
+	if err != nil {
+		fatal(err)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "code\tназвание\tпуть")
+	for _, match := range tree.Search(*search) {
+		fmt.Fprintf(w, "%d\t%s\t%s\n", match.Area.Code(), match.Area.Name, match.Path)
+	}
+	if err := w.Flush(); err != nil {
+		fatal(err)
+	}
+}
+
+// resolveAreas проставляет коды городов по справочнику. Если справочник не нужен
+// для разрешения названий и недоступен, проверка кодов пропускается.
+func resolveAreas(cfg *config.Config) error {
+	tree, err := areas.Load(areasOptions(cfg))
+	if err != nil {
+		if areas.NeedsResolve(cfg.Cities) {
+			return err
+		}
+		slog.Warn("areas directory unavailable, city codes are not checked", "error", err)
+		return nil
+	}
+
+	cities, err := areas.Resolve(cfg.Cities, tree)
+	if err != nil {
+		return err
+	}
+	cfg.Cities = cities
+
+	return nil
+}
+
+func areasOptions(cfg *config.Config) areas.Options {
+	return areas.Options{
+		URL:       cfg.Areas.Url,
+		CacheFile: cfg.Areas.CacheFile,
+		CacheTTL:  cfg.Areas.CacheTTL,
+		Timeout:   cfg.Parser.Timeout,
+	}
+}
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"hhparser/internal/config"
+	"hhparser/internal/metrics"
+	"hhparser/internal/run"
+	"hhparser/internal/scheduler"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+	"time"
+)
+
+// daemon запускает сбор по расписаниям из секции daemon.schedules до получения SIGINT/SIGTERM
+func daemon() {
+	cfg := loadConfig()
+
+	jobs, err := scheduleJobs(cfg)
+	if err != nil {
+		fatal(err)
+	}
+
+	s, err := scheduler.New(jobs, cfg.Daemon.StateFile, logScheduleEvent)
+	if err != nil {
+		fatal(err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if cfg.Daemon.Listen != "" {
+		registerVacancyMetrics(cfg)
+
+		mux := http.NewServeMux()
+		mux.Handle("GET /metrics", metrics.Default.Handler())
+		go func() {
+			if err := listen(ctx, cfg.Daemon.Listen, mux); err != nil {
+				fatal(err)
+			}
+		}()
+	}
+
+	slog.Info("daemon started", "schedules", len(jobs))
+	s.Run(ctx)
+	slog.Info("daemon stopped")
+}
+
+func scheduleJobs(cfg *config.Config) ([]*scheduler.Job, error) {
+	if len(cfg.Daemon.Schedules) == 0 {
+		return nil, fmt.Errorf("для режима daemon нужно хотя бы одно расписание в daemon.schedules")
+	}
+
+	jobs := make([]*scheduler.Job, 0, len(cfg.Daemon.Schedules))
+	names := make(map[string]bool)
+
+	// Сборы в одну директорию пишут один дневной срез и реестр: по одному за раз
+	locks := make(map[string]chan struct{})
+
+	for _, schedule := range cfg.Daemon.Schedules {
+		if schedule.Name == "" || names[schedule.Name] {
+			return nil, fmt.Errorf("у расписания должно быть уникальное имя: %q", schedule.Name)
+		}
+		names[schedule.Name] = true
+
+		cron, err := scheduler.ParseCron(schedule.Cron)
+		if err != nil {
+			return nil, fmt.Errorf("расписание %s: %w", schedule.Name, err)
+		}
+
+		job := &scheduler.Job{
+			Name:    schedule.Name,
+			Cron:    cron,
+			Jitter:  time.Duration(schedule.JitterSeconds) * time.Second,
+			CatchUp: schedule.CatchUp,
+		}
+
+		if schedule.Digest != "" {
+			period := schedule.Digest
+			job.Run = func(ctx context.Context) error {
+				return sendDigest(cfg, period)
+			}
+			jobs = append(jobs, job)
+			continue
+		}
+
+		subset, err := cfg.Subset(schedule.Cities, schedule.Technologies)
+		if err != nil {
+			return nil, fmt.Errorf("расписание %s: %w", schedule.Name, err)
+		}
+		subset.Output.Directory = scheduleDirectory(cfg, schedule)
+
+		dir := filepath.Clean(subset.Output.Directory)
+		if locks[dir] == nil {
+			locks[dir] = make(chan struct{}, 1)
+		}
+		lock := locks[dir]
+
+		job.Run = func(ctx context.Context) error {
+			select {
+			case lock <- struct{}{}:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+			defer func() { <-lock }()
+
+			_, err := run.Collect(ctx, subset, nil)
+			return err
+		}
+		jobs = append(jobs, job)
+	}
+
+	return jobs, nil
+}
+
+// scheduleDirectory — частичные расписания по умолчанию пишут в отдельную
+// поддиректорию, чтобы не перезаписывать полный дневной срез.
+func scheduleDirectory(cfg *config.Config, schedule config.ScheduleConfig) string {
+	if schedule.Directory != "" {
+		return schedule.Directory
+	}
+	if len(schedule.Cities) > 0 || len(schedule.Technologies) > 0 {
+		return filepath.Join(cfg.Output.Directory, schedule.Name)
+	}
+	return cfg.Output.Directory
+}
+
+func logScheduleEvent(event scheduler.Event) {
+	logger := slog.With("job", event.Job, "catch_up", event.CatchUp)
+
+	switch {
+	case event.Err == scheduler.ErrAlreadyRunning:
+		logger.Warn("run skipped: previous run is still in progress")
+	case event.Err != nil:
+		logger.Error("run failed", "duration", event.Duration, "error", event.Err)
+	default:
+		logger.Info("run finished", "duration", event.Duration)
+	}
+}
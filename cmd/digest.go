@@ -0,0 +1,67 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"hhparser/internal/config"
+	"hhparser/internal/notify"
+	"hhparser/internal/storage"
+	"log/slog"
+	"os"
+	"time"
+)
+
+// digestCommand строит дайджест по сохранённым запускам и отправляет его по SMTP
+func digestCommand(args []string) {
+	flags := flag.NewFlagSet("digest", flag.ExitOnError)
+	period := flags.String("period", notify.PeriodDaily, "период: daily или weekly")
+	dryRun := flags.Bool("dry-run", false, "вывести письмо в stdout вместо отправки")
+	_ = flags.Parse(args)
+
+	cfg := loadConfig()
+
+	if *dryRun {
+		digest, err := buildDigest(cfg, *period)
+		if err != nil {
+			fatal(err)
+		}
+		message, err := notify.DigestMessage(cfg.Email, digest, time.Now())
+		if err != nil {
+			fatal(err)
+		}
+		os.Stdout.Write(message)
+		return
+	}
+
+	if err := sendDigest(cfg, *period); err != nil {
+		fatal(err)
+	}
+}
+
+func sendDigest(cfg *config.Config, period string) error {
+	if cfg.Email.Host == "" || len(cfg.Email.To) == 0 {
+		return fmt.Errorf("для дайджеста нужно указать email.host и email.to")
+	}
+
+	digest, err := buildDigest(cfg, period)
+	if err != nil {
+		return err
+	}
+
+	if err := notify.SendDigest(cfg.Email, digest); err != nil {
+		return err
+	}
+
+	slog.Info("digest sent", "period", period, "from", digest.From, "to", digest.To, "recipients", len(cfg.Email.To))
+	return nil
+}
+
+func buildDigest(cfg *config.Config, period string) (notify.Digest, error) {
+	from := time.Now().AddDate(0, 0, -cfg.Email.HistoryDays)
+	history, err := storage.LoadHistory(cfg.Output.Directory, from, time.Time{})
+	if err != nil {
+		return notify.Digest{}, err
+	}
+
+	return notify.BuildDigest(history, period, cfg.Email.TopMovers, cfg.Email.AnomalyZ)
+}
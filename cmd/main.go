@@ -1,33 +1,93 @@
 package main
 
 import (
+	"context"
+	"flag"
 	"fmt"
 	"hhparser/internal/config"
-	"hhparser/internal/hhparser"
-	"hhparser/internal/storage"
-	"log"
-	"time"
+	"hhparser/internal/httpcache"
+	"hhparser/internal/logging"
+	"hhparser/internal/run"
+	"log/slog"
+	"os"
+	"strings"
 )
 
+// version задаётся при сборке: -ldflags "-X main.version=..."
+var version = "dev"
+
 func main() {
+	command, args := "run", os.Args[1:]
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		command, args = args[0], args[1:]
+	}
+
+	run.Version = version
+
+	switch command {
+	case "run":
+		runCommand(args)
+	case "list-areas":
+		listAreas(args)
+	case "daemon":
+		daemon()
+	case "serve":
+		serve()
+	case "digest":
+		digestCommand(args)
+	default:
+		fatal(fmt.Errorf("неизвестная команда %q, доступны: run, daemon, serve, digest, list-areas", command))
+	}
+}
+
+// fatal логирует ошибку и завершает процесс с кодом 1
+func fatal(err error) {
+	slog.Error(err.Error())
+	os.Exit(1)
+}
+
+func runCommand(args []string) {
+	flags := flag.NewFlagSet("run", flag.ExitOnError)
+	cacheMode := flags.String("cache", "", "режим кеша ответов hh.ru: cache, record, replay или off (по умолчанию parser.cache.mode)")
+	_ = flags.Parse(args)
+
+	cfg := loadConfig()
+	switch *cacheMode {
+	case "":
+	case "off":
+		cfg.Parser.Cache.Mode = httpcache.ModeOff
+	default:
+		cfg.Parser.Cache.Mode = *cacheMode
+		if err := cfg.Validate(); err != nil {
+			fatal(err)
+		}
+	}
+
+	bar := newProgressBar(os.Stdout)
+	if _, err := run.Collect(context.Background(), cfg, bar.Report); err != nil {
+		fatal(err)
+	}
+}
+
+// loadConfig загружает конфиг, настраивает логи, раскрывает регионы по справочнику
+// и проверяет результат
+func loadConfig() *config.Config {
 	cfg, err := config.Load()
 	if err != nil {
-		log.Fatal(err)
+		fatal(err)
 	}
 
-	if err := cfg.Validate(); err != nil {
-		log.Fatal(err)
+	if err := logging.Setup(cfg.Log); err != nil {
+		fatal(err)
 	}
 
-	startTime := time.Now()
-	fmt.Printf("Старт парсинга: %s\n", startTime.Format("15:04:05"))
+	if err := resolveAreas(cfg); err != nil {
+		fatal(err)
+	}
 
-	vacancy := hhparser.GetAllVacancy(hhparser.NewParserConfig(cfg))
-	if err := storage.SaveStatistics(vacancy, storage.NewStorageConfig(cfg)); err != nil {
-		log.Fatal(err)
+	if err := cfg.Validate(); err != nil {
+		fatal(err)
 	}
 
-	endTime := time.Now()
-	fmt.Printf("Завершено: %s\n", endTime.Format("15:04:05"))
-	fmt.Printf("Длительность: %v\n", endTime.Sub(startTime))
+	return cfg
 }
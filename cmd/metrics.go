@@ -0,0 +1,61 @@
+package main
+
+import (
+	"hhparser/internal/config"
+	"hhparser/internal/metrics"
+	"hhparser/internal/storage"
+	"path/filepath"
+)
+
+// registerVacancyMetrics добавляет gauge hh_vacancies, который при каждом опросе
+// читает последний сохранённый запуск output.directory и директорий расписаний.
+// Метка schedule — имя расписания со своей директорией, пустая — output.directory.
+func registerVacancyMetrics(cfg *config.Config) {
+	type source struct{ schedule, directory string }
+	sources := []source{{"", cfg.Output.Directory}}
+	seen := map[string]bool{filepath.Clean(cfg.Output.Directory): true}
+	for _, schedule := range cfg.Daemon.Schedules {
+		if schedule.Digest != "" {
+			continue
+		}
+		dir := scheduleDirectory(cfg, schedule)
+		if seen[filepath.Clean(dir)] {
+			continue
+		}
+		seen[filepath.Clean(dir)] = true
+		sources = append(sources, source{schedule.Name, dir})
+	}
+
+	metrics.Default.Register(metrics.NewGaugeFunc("hh_vacancies",
+		"Количество вакансий в последнем сохранённом запуске",
+		[]string{"technology", "city", "category", "schedule"},
+		func() []metrics.Sample {
+			var samples []metrics.Sample
+			for _, source := range sources {
+				stats, err := storage.LoadLatest(source.directory)
+				if err != nil {
+					continue
+				}
+				samples = append(samples, vacancySamples(stats, source.schedule)...)
+			}
+			return samples
+		}))
+}
+
+func vacancySamples(stats storage.Statistics, schedule string) []metrics.Sample {
+	var samples []metrics.Sample
+	for _, city := range stats.Cities {
+		for _, tech := range stats.Technologies {
+			key := tech.Key()
+			count, ok := city.Vacancies[key]
+			if !ok {
+				continue
+			}
+			samples = append(samples, metrics.Sample{
+				Labels: []string{key, city.Name, tech.Category, schedule},
+				Value:  float64(count),
+			})
+		}
+	}
+	return samples
+}
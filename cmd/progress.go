@@ -0,0 +1,105 @@
+package main
+
+import (
+	"fmt"
+	"hhparser/internal/hhparser"
+	"log/slog"
+	"os"
+	"strings"
+	"time"
+)
+
+const (
+	progressBarWidth = 30
+	// Без терминала прогресс пишется в лог не чаще этого интервала
+	progressLogInterval = 10 * time.Second
+)
+
+// progressBar показывает ход сбора: полосу с ETA в терминале
+// или периодические строки лога, если stdout не терминал
+type progressBar struct {
+	out     *os.File
+	tty     bool
+	start   time.Time
+	lastLog time.Time
+
+	done    int
+	total   int
+	failed  int
+	retries int
+}
+
+func newProgressBar(out *os.File) *progressBar {
+	return &progressBar{out: out, tty: isTerminal(out), start: time.Now()}
+}
+
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// Report — обработчик hhparser.ProgressFunc
+func (b *progressBar) Report(event hhparser.ProgressEvent) {
+	b.done, b.total = event.Done, event.Total
+
+	switch event.Type {
+	case hhparser.ProgressRetried:
+		b.retries++
+	case hhparser.ProgressFailed:
+		b.failed++
+	case hhparser.ProgressStarted:
+		return
+	}
+
+	if b.tty {
+		fmt.Fprintf(b.out, "\r%s", b.line())
+		if b.done == b.total {
+			fmt.Fprintln(b.out)
+		}
+		return
+	}
+
+	if time.Since(b.lastLog) >= progressLogInterval || b.done == b.total {
+		b.lastLog = time.Now()
+		slog.Info("progress",
+			"done", b.done,
+			"total", b.total,
+			"failed", b.failed,
+			"retries", b.retries,
+			"eta", b.eta().Round(time.Second))
+	}
+}
+
+func (b *progressBar) line() string {
+	filled := 0
+	if b.total > 0 {
+		filled = progressBarWidth * b.done / b.total
+	}
+	bar := strings.Repeat("█", filled) + strings.Repeat("░", progressBarWidth-filled)
+
+	line := fmt.Sprintf("[%s] %d/%d %3d%%", bar, b.done, b.total, percentOf(b.done, b.total))
+	if b.failed > 0 {
+		line += fmt.Sprintf(" ошибок: %d", b.failed)
+	}
+	if b.done < b.total {
+		line += fmt.Sprintf(" ETA %v", b.eta().Round(time.Second))
+	}
+	// Затираем хвост более длинной предыдущей строки
+	return line + "   "
+}
+
+// eta оценивает оставшееся время по средней длительности завершённых запросов
+func (b *progressBar) eta() time.Duration {
+	if b.done == 0 || b.done >= b.total {
+		return 0
+	}
+	perQuery := time.Since(b.start) / time.Duration(b.done)
+	return perQuery * time.Duration(b.total-b.done)
+}
+
+func percentOf(done, total int) int {
+	if total == 0 {
+		return 0
+	}
+	return 100 * done / total
+}
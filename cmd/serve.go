@@ -0,0 +1,62 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"hhparser/internal/metrics"
+	"hhparser/internal/server"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+// serve запускает HTTP API над сохранёнными запусками
+func serve() {
+	cfg := loadConfig()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	registerVacancyMetrics(cfg)
+
+	api := server.New(server.NewServerConfig(cfg))
+	api.Handle("GET /metrics", metrics.Default.Handler())
+	if err := listen(ctx, cfg.Server.Listen, api.Handler()); err != nil {
+		fatal(err)
+	}
+}
+
+// listen обслуживает запросы до отмены контекста, затем корректно закрывает соединения
+func listen(ctx context.Context, addr string, handler http.Handler) error {
+	srv := &http.Server{
+		Addr:              addr,
+		Handler:           handler,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		slog.Info("http server listening", "addr", addr)
+		errCh <- srv.ListenAndServe()
+	}()
+
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		return err
+	}
+	if err := <-errCh; !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
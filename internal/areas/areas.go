@@ -0,0 +1,249 @@
+package areas
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	ErrAreaNotFound  = errors.New("areas: регион не найден в справочнике hh.ru")
+	ErrAreaAmbiguous = errors.New("areas: название региона неоднозначно, укажите code")
+)
+
+// Area — узел справочника регионов hh.ru (https://api.hh.ru/areas)
+type Area struct {
+	ID       string  `json:"id"`
+	ParentID *string `json:"parent_id"`
+	Name     string  `json:"name"`
+	Areas    []*Area `json:"areas"`
+}
+
+// Code возвращает числовой код региона, который используется в параметре area
+func (a *Area) Code() int {
+	code, _ := strconv.Atoi(a.ID)
+	return code
+}
+
+// Tree — загруженный справочник с индексами по коду и названию
+type Tree struct {
+	roots  []*Area
+	byCode map[int]*Area
+	parent map[int]*Area
+	byName map[string][]*Area
+}
+
+// Match — результат поиска по справочнику
+type Match struct {
+	Area *Area
+	Path string
+}
+
+type Options struct {
+	URL       string
+	CacheFile string
+	CacheTTL  time.Duration
+	Timeout   time.Duration
+}
+
+// Load читает справочник из кэш-файла, а если его нет или он устарел — скачивает с API
+// и обновляет кэш. При недоступности API или неразборчивом ответе используется устаревший кэш.
+func Load(opts Options) (*Tree, error) {
+	cached, cacheErr := readCache(opts.CacheFile, opts.CacheTTL)
+	if cacheErr == nil && cached.fresh {
+		return NewTree(cached.roots), nil
+	}
+
+	if opts.URL == "" {
+		if cached != nil {
+			return NewTree(cached.roots), nil
+		}
+		return nil, fmt.Errorf("failed to load areas: %w", cacheErr)
+	}
+
+	content, err := download(opts.URL, opts.Timeout)
+	if err != nil {
+		if cached != nil {
+			slog.Warn("areas not downloaded, using stale cache", "file", opts.CacheFile, "error", err)
+			return NewTree(cached.roots), nil
+		}
+		return nil, fmt.Errorf("failed to download areas: %w", err)
+	}
+
+	roots, err := Parse(content)
+	if err != nil {
+		if cached != nil {
+			slog.Warn("downloaded areas not parsed, using stale cache", "file", opts.CacheFile, "error", err)
+			return NewTree(cached.roots), nil
+		}
+		return nil, err
+	}
+
+	if opts.CacheFile != "" {
+		if err := writeCache(opts.CacheFile, content); err != nil {
+			return nil, err
+		}
+	}
+
+	return NewTree(roots), nil
+}
+
+// Parse разбирает JSON справочника в формате API hh.ru
+func Parse(content []byte) ([]*Area, error) {
+	var roots []*Area
+	if err := json.Unmarshal(content, &roots); err != nil {
+		return nil, fmt.Errorf("failed to parse areas: %w", err)
+	}
+	return roots, nil
+}
+
+func NewTree(roots []*Area) *Tree {
+	tree := &Tree{
+		roots:  roots,
+		byCode: make(map[int]*Area),
+		parent: make(map[int]*Area),
+		byName: make(map[string][]*Area),
+	}
+
+	var walk func(parent *Area, areas []*Area)
+	walk = func(parent *Area, areas []*Area) {
+		for _, area := range areas {
+			tree.byCode[area.Code()] = area
+			if parent != nil {
+				tree.parent[area.Code()] = parent
+			}
+			name := normalize(area.Name)
+			tree.byName[name] = append(tree.byName[name], area)
+			walk(area, area.Areas)
+		}
+	}
+	walk(nil, roots)
+
+	return tree
+}
+
+// Find ищет регион по коду
+func (t *Tree) Find(code int) (*Area, bool) {
+	area, ok := t.byCode[code]
+	return area, ok
+}
+
+// FindByName ищет регион по точному (без учёта регистра) названию
+func (t *Tree) FindByName(name string) (*Area, error) {
+	found := t.byName[normalize(name)]
+	switch len(found) {
+	case 0:
+		return nil, fmt.Errorf("%w: %q", ErrAreaNotFound, name)
+	case 1:
+		return found[0], nil
+	}
+
+	candidates := make([]string, 0, len(found))
+	for _, area := range found {
+		candidates = append(candidates, fmt.Sprintf("%s (code %s)", t.Path(area), area.ID))
+	}
+	return nil, fmt.Errorf("%w: %q — %s", ErrAreaAmbiguous, name, strings.Join(candidates, "; "))
+}
+
+// Search возвращает регионы, в названии которых встречается query
+func (t *Tree) Search(query string) []Match {
+	query = normalize(query)
+
+	var matches []Match
+	for _, area := range t.byCode {
+		if strings.Contains(normalize(area.Name), query) {
+			matches = append(matches, Match{Area: area, Path: t.Path(area)})
+		}
+	}
+
+	sort.Slice(matches, func(i, j int) bool {
+		return matches[i].Area.Code() < matches[j].Area.Code()
+	})
+
+	return matches
+}
+
+// Path возвращает полный путь региона, например "Россия / Краснодарский край / Сочи"
+func (t *Tree) Path(area *Area) string {
+	names := []string{area.Name}
+	for parent := t.parent[area.Code()]; parent != nil; parent = t.parent[parent.Code()] {
+		names = append([]string{parent.Name}, names...)
+	}
+	return strings.Join(names, " / ")
+}
+
+func normalize(name string) string {
+	return strings.ToLower(strings.TrimSpace(strings.ReplaceAll(name, "ё", "е")))
+}
+
+type cacheContent struct {
+	roots []*Area
+	fresh bool
+}
+
+func readCache(path string, ttl time.Duration) (*cacheContent, error) {
+	if path == "" {
+		return nil, errors.New("cache file is not configured")
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	roots, err := Parse(content)
+	if err != nil {
+		return nil, err
+	}
+
+	// ttl == 0 — файл считается всегда актуальным (например, поставляемый вместе с программой)
+	fresh := ttl == 0 || time.Since(info.ModTime()) < ttl
+
+	return &cacheContent{roots: roots, fresh: fresh}, nil
+}
+
+func writeCache(path string, content []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create areas cache directory: %w", err)
+	}
+	if err := os.WriteFile(path, content, 0o644); err != nil {
+		return fmt.Errorf("failed to write areas cache: %w", err)
+	}
+	return nil
+}
+
+func download(url string, timeout time.Duration) ([]byte, error) {
+	client := &http.Client{Timeout: timeout}
+
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", "hhparser/1.0")
+
+	res, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %d", res.StatusCode)
+	}
+
+	return io.ReadAll(res.Body)
+}
@@ -0,0 +1,182 @@
+package areas
+
+import (
+	"hhparser/internal/config"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testAreas = `[
+  {"id": "113", "parent_id": null, "name": "Россия", "areas": [
+    {"id": "1", "parent_id": "113", "name": "Москва", "areas": []},
+    {"id": "1438", "parent_id": "113", "name": "Краснодарский край", "areas": [
+      {"id": "53", "parent_id": "1438", "name": "Краснодар", "areas": []},
+      {"id": "237", "parent_id": "1438", "name": "Сочи", "areas": []}
+    ]},
+    {"id": "1530", "parent_id": "113", "name": "Ростовская область", "areas": [
+      {"id": "76", "parent_id": "1530", "name": "Ростов-на-Дону", "areas": []}
+    ]}
+  ]},
+  {"id": "16", "parent_id": null, "name": "Беларусь", "areas": [
+    {"id": "1002", "parent_id": "16", "name": "Минск", "areas": []},
+    {"id": "9999", "parent_id": "16", "name": "Сочи", "areas": []}
+  ]}
+]`
+
+func testTree(t *testing.T) *Tree {
+	roots, err := Parse([]byte(testAreas))
+	require.NoError(t, err)
+	return NewTree(roots)
+}
+
+func TestTree_FindAndPath(t *testing.T) {
+	tree := testTree(t)
+
+	area, ok := tree.Find(53)
+	require.True(t, ok)
+	assert.Equal(t, "Краснодар", area.Name)
+	assert.Equal(t, "Россия / Краснодарский край / Краснодар", tree.Path(area))
+
+	_, ok = tree.Find(12345)
+	assert.False(t, ok)
+}
+
+func TestTree_FindByName(t *testing.T) {
+	tree := testTree(t)
+
+	area, err := tree.FindByName("  краснодар ")
+	require.NoError(t, err)
+	assert.Equal(t, 53, area.Code())
+
+	_, err = tree.FindByName("Сочи")
+	assert.ErrorIs(t, err, ErrAreaAmbiguous)
+
+	_, err = tree.FindByName("Атлантида")
+	assert.ErrorIs(t, err, ErrAreaNotFound)
+}
+
+func TestTree_Search(t *testing.T) {
+	tree := testTree(t)
+
+	matches := tree.Search("краснодар")
+	require.Len(t, matches, 2)
+	assert.Equal(t, 53, matches[0].Area.Code())
+	assert.Equal(t, 1438, matches[1].Area.Code())
+}
+
+func TestResolve(t *testing.T) {
+	tree := testTree(t)
+
+	cities := []config.CityConfig{
+		{ID: 1, Name: "MOSCOW", Code: 1, Enabled: true},
+		{ID: 2, Name: "KRASNODAR", Area: "Краснодар", Enabled: true},
+		{ID: 3, Name: "KUBAN", Code: 1438, IncludeChildren: true, Enabled: true},
+	}
+
+	resolved, err := Resolve(cities, tree)
+	require.NoError(t, err)
+	require.Len(t, resolved, 3, "Краснодар из края уже задан отдельно")
+
+	assert.Equal(t, 1, resolved[0].Code)
+	assert.Equal(t, 53, resolved[1].Code)
+	assert.Equal(t, "KRASNODAR", resolved[1].Name)
+	assert.Equal(t, "Сочи", resolved[2].Name)
+	assert.Equal(t, 237, resolved[2].Code)
+	assert.Equal(t, 4, resolved[2].ID)
+}
+
+func TestResolve_Errors(t *testing.T) {
+	tree := testTree(t)
+
+	tests := []struct {
+		name string
+		city config.CityConfig
+	}{
+		{name: "неизвестный код", city: config.CityConfig{Name: "X", Code: 424242}},
+		{name: "нет code и area", city: config.CityConfig{Name: "X"}},
+		{name: "неоднозначное название", city: config.CityConfig{Name: "X", Area: "Сочи"}},
+		{name: "нет дочерних регионов", city: config.CityConfig{Name: "X", Code: 1, IncludeChildren: true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Resolve([]config.CityConfig{tt.city}, tree)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestLoad_DownloadsAndCaches(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		_, _ = w.Write([]byte(testAreas))
+	}))
+	defer server.Close()
+
+	cacheFile := filepath.Join(t.TempDir(), "areas.json")
+	opts := Options{URL: server.URL, CacheFile: cacheFile, CacheTTL: time.Hour, Timeout: time.Second}
+
+	tree, err := Load(opts)
+	require.NoError(t, err)
+	_, ok := tree.Find(1002)
+	assert.True(t, ok)
+
+	_, err = os.Stat(cacheFile)
+	require.NoError(t, err, "справочник должен сохраниться в кэш")
+
+	_, err = Load(opts)
+	require.NoError(t, err)
+	assert.Equal(t, 1, requests, "свежий кэш не должен приводить к повторной загрузке")
+}
+
+func TestLoad_StaleCacheWhenAPIUnavailable(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	cacheFile := filepath.Join(t.TempDir(), "areas.json")
+	require.NoError(t, os.WriteFile(cacheFile, []byte(testAreas), 0o644))
+	old := time.Now().Add(-48 * time.Hour)
+	require.NoError(t, os.Chtimes(cacheFile, old, old))
+
+	tree, err := Load(Options{URL: server.URL, CacheFile: cacheFile, CacheTTL: time.Hour, Timeout: time.Second})
+	require.NoError(t, err)
+
+	_, ok := tree.Find(53)
+	assert.True(t, ok)
+}
+
+func TestLoad_StaleCacheWhenResponseBroken(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`<html>maintenance</html>`))
+	}))
+	defer server.Close()
+
+	cacheFile := filepath.Join(t.TempDir(), "areas.json")
+	require.NoError(t, os.WriteFile(cacheFile, []byte(testAreas), 0o644))
+	old := time.Now().Add(-48 * time.Hour)
+	require.NoError(t, os.Chtimes(cacheFile, old, old))
+
+	opts := Options{URL: server.URL, CacheFile: cacheFile, CacheTTL: time.Hour, Timeout: time.Second}
+	tree, err := Load(opts)
+	require.NoError(t, err)
+	_, ok := tree.Find(53)
+	assert.True(t, ok)
+
+	content, err := os.ReadFile(cacheFile)
+	require.NoError(t, err)
+	assert.JSONEq(t, testAreas, string(content), "неразборчивый ответ не перезаписывает кэш")
+
+	opts.CacheFile = filepath.Join(t.TempDir(), "missing.json")
+	_, err = Load(opts)
+	assert.Error(t, err, "без кэша ошибка разбора возвращается")
+}
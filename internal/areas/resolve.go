@@ -0,0 +1,100 @@
+package areas
+
+import (
+	"fmt"
+	"hhparser/internal/config"
+	"log/slog"
+)
+
+// NeedsResolve сообщает, что в конфиге есть города, которые нельзя обработать без справочника
+func NeedsResolve(cities []config.CityConfig) bool {
+	for _, city := range cities {
+		if city.Code == 0 || city.IncludeChildren {
+			return true
+		}
+	}
+	return false
+}
+
+// Resolve проставляет коды городам, заданным по названию (area), проверяет,
+// что все коды есть в справочнике, и раскрывает регионы с include_children
+// в список дочерних регионов. Повторный код (например, город задан отдельно и ещё раз
+// через include_children своего края) пропускается: остаётся первое упоминание.
+func Resolve(cities []config.CityConfig, tree *Tree) ([]config.CityConfig, error) {
+	resolved := make([]config.CityConfig, 0, len(cities))
+	codes := make(map[int]string)
+
+	add := func(city config.CityConfig) bool {
+		if name, ok := codes[city.Code]; ok {
+			slog.Warn("duplicate area skipped", "city", city.Name, "code", city.Code, "kept", name)
+			return false
+		}
+		codes[city.Code] = city.Name
+		resolved = append(resolved, city)
+		return true
+	}
+
+	nextID := 0
+	for _, city := range cities {
+		if city.ID > nextID {
+			nextID = city.ID
+		}
+	}
+
+	for _, city := range cities {
+		area, err := findArea(city, tree)
+		if err != nil {
+			return nil, err
+		}
+
+		if city.Code == 0 {
+			city.Code = area.Code()
+		}
+		if city.Name == "" {
+			city.Name = area.Name
+		}
+
+		if !city.IncludeChildren {
+			add(city)
+			continue
+		}
+
+		if len(area.Areas) == 0 {
+			return nil, fmt.Errorf("у региона %s (code %d) нет дочерних регионов", area.Name, city.Code)
+		}
+
+		for _, child := range area.Areas {
+			if add(config.CityConfig{
+				ID:      nextID + 1,
+				Name:    child.Name,
+				Code:    child.Code(),
+				Area:    child.Name,
+				Enabled: true,
+			}) {
+				nextID++
+			}
+		}
+	}
+
+	return resolved, nil
+}
+
+func findArea(city config.CityConfig, tree *Tree) (*Area, error) {
+	if city.Code != 0 {
+		area, ok := tree.Find(city.Code)
+		if !ok {
+			return nil, fmt.Errorf("город %s: %w: code %d", city.Name, ErrAreaNotFound, city.Code)
+		}
+		return area, nil
+	}
+
+	if city.Area == "" {
+		return nil, fmt.Errorf("город %s: нужно указать code или area", city.Name)
+	}
+
+	area, err := tree.FindByName(city.Area)
+	if err != nil {
+		return nil, fmt.Errorf("город %s: %w", city.Name, err)
+	}
+	return area, nil
+}
@@ -0,0 +1,60 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfig_Hash(t *testing.T) {
+	cfg := &Config{
+		Cities:       []CityConfig{{Name: "MOSCOW", Code: 1, Enabled: true}},
+		Technologies: []TechnologyConfig{{Name: "Golang", Search: "golang", Enabled: true}},
+		Email:        EmailConfig{Password: "secret"},
+	}
+
+	hash := cfg.Hash()
+	assert.Len(t, hash, 64)
+
+	cfg.Email.Password = "other"
+	cfg.Notify.Webhooks = []WebhookConfig{{Name: "slack"}}
+	assert.Equal(t, hash, cfg.Hash(), "секреты и уведомления не влияют на хеш")
+
+	cfg.Technologies[0].Filters = &FilterConfig{Schedule: "remote"}
+	assert.NotEqual(t, hash, cfg.Hash())
+}
+
+func TestConfig_ValidateGroups(t *testing.T) {
+	cfg := &Config{
+		Cities: []CityConfig{
+			{Name: "MOSCOW", Code: 1, Enabled: true},
+			{Name: "KRASNODAR", Code: 53, Enabled: true},
+			{Name: "SOCHI", Code: 237},
+		},
+		Technologies: []TechnologyConfig{{Name: "Golang", Search: "golang", Enabled: true}},
+		Parser:       ParserConfig{MaxGoroutines: 1},
+		Groups:       []GroupConfig{{Name: "South", Cities: []string{"KRASNODAR", "SOCHI"}}},
+	}
+	cfg.filterEnabled()
+	assert.NoError(t, cfg.Validate(), "выключенный город в группе допустим")
+
+	cfg.Groups[0].Cities = append(cfg.Groups[0].Cities, "ROSTOW")
+	assert.ErrorContains(t, cfg.Validate(), "ROSTOW")
+}
+
+func TestLoadFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("parser:\n  timeout_seconds: 3\n"), 0o644))
+
+	cfg, err := LoadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, 3*time.Second, cfg.Parser.Timeout)
+	assert.Equal(t, 4, cfg.Parser.MaxGoroutines, "значения по умолчанию")
+
+	_, err = LoadFile(filepath.Join(t.TempDir(), "missing.yaml"))
+	assert.Error(t, err, "явно указанный файл обязан существовать")
+}
@@ -0,0 +1,183 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// FilterConfig — дополнительные фильтры поиска hh.ru.
+// Пустые значения означают отсутствие фильтра. OnlyWithSalary — указатель,
+// чтобы технология могла явным false отменить глобальный only_with_salary: true.
+type FilterConfig struct {
+	Experience     string `mapstructure:"experience" json:"experience,omitempty"`
+	SalaryFrom     int    `mapstructure:"salary_from" json:"salaryFrom,omitempty"`
+	OnlyWithSalary *bool  `mapstructure:"only_with_salary" json:"onlyWithSalary,omitempty"`
+	Schedule       string `mapstructure:"schedule" json:"schedule,omitempty"`
+	Employment     string `mapstructure:"employment" json:"employment,omitempty"`
+	SearchPeriod   int    `mapstructure:"search_period" json:"searchPeriod,omitempty"`
+}
+
+var (
+	allowedExperience   = []string{"noExperience", "between1And3", "between3And6", "moreThan6"}
+	allowedSchedule     = []string{"fullDay", "shift", "flexible", "remote", "flyInFly"}
+	allowedEmployment   = []string{"full", "part", "project", "volunteer", "probation"}
+	allowedSearchPeriod = []int{0, 1, 3, 7, 30}
+)
+
+// IsEmpty сообщает, что ни один фильтр не задан.
+// only_with_salary: false равносилен отсутствию фильтра.
+func (f FilterConfig) IsEmpty() bool {
+	if f.withSalary() {
+		return false
+	}
+	f.OnlyWithSalary = nil
+	return f == FilterConfig{}
+}
+
+// withSalary сообщает, включён ли only_with_salary
+func (f FilterConfig) withSalary() bool {
+	return f.OnlyWithSalary != nil && *f.OnlyWithSalary
+}
+
+// Merge возвращает фильтры f, дополненные непустыми значениями override.
+func (f FilterConfig) Merge(override FilterConfig) FilterConfig {
+	result := f
+	if override.Experience != "" {
+		result.Experience = override.Experience
+	}
+	if override.SalaryFrom != 0 {
+		result.SalaryFrom = override.SalaryFrom
+	}
+	if override.OnlyWithSalary != nil {
+		result.OnlyWithSalary = override.OnlyWithSalary
+	}
+	if override.Schedule != "" {
+		result.Schedule = override.Schedule
+	}
+	if override.Employment != "" {
+		result.Employment = override.Employment
+	}
+	if override.SearchPeriod != 0 {
+		result.SearchPeriod = override.SearchPeriod
+	}
+	return result
+}
+
+// Values переводит фильтры в параметры запроса страницы поиска hh.ru.
+func (f FilterConfig) Values() url.Values {
+	values := url.Values{}
+	if f.Experience != "" {
+		values.Set("experience", f.Experience)
+	}
+	if f.SalaryFrom > 0 {
+		values.Set("salary", strconv.Itoa(f.SalaryFrom))
+	}
+	if f.withSalary() {
+		values.Set("only_with_salary", "true")
+	}
+	if f.Schedule != "" {
+		values.Set("schedule", f.Schedule)
+	}
+	if f.Employment != "" {
+		values.Set("employment", f.Employment)
+	}
+	if f.SearchPeriod > 0 {
+		values.Set("search_period", strconv.Itoa(f.SearchPeriod))
+	}
+	return values
+}
+
+// APIValues переводит фильтры в параметры запроса API hh.ru: период поиска
+// там называется period, а не search_period.
+func (f FilterConfig) APIValues() url.Values {
+	values := f.Values()
+	if period := values.Get("search_period"); period != "" {
+		values.Del("search_period")
+		values.Set("period", period)
+	}
+	return values
+}
+
+// Key возвращает каноническое представление набора фильтров,
+// например "experience=between3And6,schedule=remote".
+func (f FilterConfig) Key() string {
+	var parts []string
+	if f.Experience != "" {
+		parts = append(parts, "experience="+f.Experience)
+	}
+	if f.SalaryFrom > 0 {
+		parts = append(parts, "salary="+strconv.Itoa(f.SalaryFrom))
+	}
+	if f.withSalary() {
+		parts = append(parts, "only_with_salary")
+	}
+	if f.Schedule != "" {
+		parts = append(parts, "schedule="+f.Schedule)
+	}
+	if f.Employment != "" {
+		parts = append(parts, "employment="+f.Employment)
+	}
+	if f.SearchPeriod > 0 {
+		parts = append(parts, "period="+strconv.Itoa(f.SearchPeriod))
+	}
+	return strings.Join(parts, ",")
+}
+
+// Apply добавляет фильтры к готовой ссылке поиска, заменяя одноимённые параметры.
+func (f FilterConfig) Apply(link string) (string, error) {
+	return f.apply(link, f.Values)
+}
+
+// ApplyAPI — то же для ссылки на API hh.ru.
+func (f FilterConfig) ApplyAPI(link string) (string, error) {
+	return f.apply(link, f.APIValues)
+}
+
+func (f FilterConfig) apply(link string, values func() url.Values) (string, error) {
+	if f.IsEmpty() {
+		return link, nil
+	}
+
+	u, err := url.Parse(link)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse search url: %w", err)
+	}
+
+	query := u.Query()
+	for name, values := range values() {
+		query[name] = values
+	}
+	u.RawQuery = query.Encode()
+
+	return u.String(), nil
+}
+
+func (f FilterConfig) validate() error {
+	if f.Experience != "" && !contains(allowedExperience, f.Experience) {
+		return fmt.Errorf("неизвестное значение experience: %q", f.Experience)
+	}
+	if f.Schedule != "" && !contains(allowedSchedule, f.Schedule) {
+		return fmt.Errorf("неизвестное значение schedule: %q", f.Schedule)
+	}
+	if f.Employment != "" && !contains(allowedEmployment, f.Employment) {
+		return fmt.Errorf("неизвестное значение employment: %q", f.Employment)
+	}
+	if !contains(allowedSearchPeriod, f.SearchPeriod) {
+		return fmt.Errorf("search_period должен быть одним из %v", allowedSearchPeriod)
+	}
+	if f.SalaryFrom < 0 {
+		return fmt.Errorf("salary_from должен быть >= 0")
+	}
+	return nil
+}
+
+func contains[T comparable](values []T, value T) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,135 @@
+package config
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFilterConfig_Merge(t *testing.T) {
+	global := FilterConfig{Experience: "between1And3", SalaryFrom: 100000}
+	own := FilterConfig{Experience: "between3And6", Schedule: "remote"}
+
+	merged := global.Merge(own)
+
+	assert.Equal(t, "between3And6", merged.Experience)
+	assert.Equal(t, 100000, merged.SalaryFrom)
+	assert.Equal(t, "remote", merged.Schedule)
+}
+
+func TestFilterConfig_MergeOnlyWithSalary(t *testing.T) {
+	yes, no := true, false
+	global := FilterConfig{OnlyWithSalary: &yes}
+
+	assert.True(t, global.Merge(FilterConfig{}).withSalary(), "без своего значения действует глобальное")
+
+	merged := global.Merge(FilterConfig{OnlyWithSalary: &no})
+	assert.False(t, merged.withSalary(), "технология отменяет глобальный only_with_salary")
+	assert.True(t, merged.IsEmpty())
+	assert.Empty(t, merged.Values().Get("only_with_salary"))
+}
+
+func TestFilterConfig_Apply(t *testing.T) {
+	link := "https://hh.ru/search/vacancy?text=C%2B%2B&salary=&area=1"
+	filters := FilterConfig{SalaryFrom: 200000, Schedule: "remote", SearchPeriod: 7}
+
+	result, err := filters.Apply(link)
+	require.NoError(t, err)
+
+	u, err := url.Parse(result)
+	require.NoError(t, err)
+
+	query := u.Query()
+	assert.Equal(t, "C++", query.Get("text"))
+	assert.Equal(t, "1", query.Get("area"))
+	assert.Equal(t, []string{"200000"}, query["salary"], "пустой salary из шаблона должен быть заменён")
+	assert.Equal(t, "remote", query.Get("schedule"))
+	assert.Equal(t, "7", query.Get("search_period"))
+}
+
+func TestFilterConfig_ApplyEmpty(t *testing.T) {
+	link := "https://hh.ru/search/vacancy?text=Team+lead&area=53"
+
+	result, err := FilterConfig{}.Apply(link)
+	require.NoError(t, err)
+	assert.Equal(t, link, result, "без фильтров ссылка не меняется")
+}
+
+func TestTechnologyConfig_Key(t *testing.T) {
+	tests := []struct {
+		name     string
+		tech     TechnologyConfig
+		expected string
+	}{
+		{
+			name:     "без фильтров",
+			tech:     TechnologyConfig{Name: "Golang"},
+			expected: "Golang",
+		},
+		{
+			name:     "пустые фильтры",
+			tech:     TechnologyConfig{Name: "Golang", Filters: &FilterConfig{}},
+			expected: "Golang",
+		},
+		{
+			name: "удалёнка и опыт",
+			tech: TechnologyConfig{Name: "Golang", Filters: &FilterConfig{
+				Schedule:   "remote",
+				Experience: "between3And6",
+			}},
+			expected: "Golang[experience=between3And6,schedule=remote]",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.tech.Key())
+		})
+	}
+}
+
+func TestConfig_ApplyFiltersAndValidate(t *testing.T) {
+	yes := true
+	cfg := Config{
+		Cities: []CityConfig{{Name: "MOSCOW", Code: 1, Enabled: true}},
+		Technologies: []TechnologyConfig{
+			{Name: "Golang", Enabled: true},
+			{Name: "Golang", Enabled: true, Filters: &FilterConfig{Schedule: "remote"}},
+		},
+		Parser:  ParserConfig{MaxGoroutines: 1},
+		Filters: FilterConfig{OnlyWithSalary: &yes},
+	}
+
+	cfg.applyFilters()
+	require.NoError(t, cfg.Validate())
+
+	assert.Equal(t, "Golang[only_with_salary]", cfg.Technologies[0].Key())
+	assert.Equal(t, "Golang[only_with_salary,schedule=remote]", cfg.Technologies[1].Key())
+
+	cfg.Technologies[1].Filters.Schedule = "sometimes"
+	assert.Error(t, cfg.Validate())
+
+	cfg.Technologies[1].Filters.Schedule = ""
+	assert.Error(t, cfg.Validate(), "одинаковые ключи серий должны быть ошибкой")
+}
+
+func TestFilterConfig_ApplyAPI(t *testing.T) {
+	link := "https://api.hh.ru/vacancies?text=golang&area=1&page=0&per_page=100"
+	yes := true
+	filters := FilterConfig{Experience: "between1And3", OnlyWithSalary: &yes, SearchPeriod: 3}
+
+	result, err := filters.ApplyAPI(link)
+	require.NoError(t, err)
+
+	u, err := url.Parse(result)
+	require.NoError(t, err)
+
+	query := u.Query()
+	assert.Equal(t, "3", query.Get("period"), "API ждёт period")
+	assert.False(t, query.Has("search_period"))
+	assert.Equal(t, "between1And3", query.Get("experience"))
+	assert.Equal(t, "true", query.Get("only_with_salary"))
+	assert.Equal(t, "golang", query.Get("text"))
+}
@@ -0,0 +1,379 @@
+// Package e2e проверяет весь путь сбора: загрузка конфига, запросы к поддельному hh.ru,
+// сохранение статистики и манифеста.
+package e2e
+
+import (
+	"context"
+	"fmt"
+	"hhparser/internal/config"
+	"hhparser/internal/hhparser"
+	"hhparser/internal/hhtest"
+	"hhparser/internal/run"
+	"hhparser/internal/storage"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const configTemplate = `
+cities:
+  - name: "MOSCOW"
+    code: 1
+    enabled: true
+  - name: "KRASNODAR"
+    code: 53
+    enabled: true
+  - name: "SOCHI"
+    code: 237
+    enabled: false
+technologies:
+  - name: "Golang"
+    search: "golang"
+    category: "languages"
+    enabled: true
+  - name: "Python"
+    search: "python"
+    category: "languages"
+    enabled: true
+  - name: "Kafka"
+    search: "kafka"
+    enabled: true
+parser:
+  max_goroutines: 2
+  timeout_seconds: 5
+  retry_count: 3
+  rate_limit_ms: 0
+  url_search_vacancies: "%s"
+  throttle:
+    max_delay_ms: 10
+groups:
+  - name: "All"
+    cities: ["MOSCOW", "KRASNODAR"]
+output:
+  directory: "%s"
+`
+
+// setup пишет конфиг во временную директорию и загружает его из этого файла
+func setup(t *testing.T, hh *hhtest.Server) *config.Config {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.yaml")
+	require.NoError(t, os.WriteFile(path,
+		[]byte(fmt.Sprintf(configTemplate, hh.SearchURL(), filepath.Join(dir, "data"))),
+		0o644))
+
+	cfg, err := config.LoadFile(path)
+	require.NoError(t, err)
+	require.NoError(t, cfg.Validate())
+
+	return cfg
+}
+
+// collect выполняет тот же сбор, что и команда run
+func collect(t *testing.T, cfg *config.Config) storage.Statistics {
+	stats, err := run.Collect(context.Background(), cfg, nil)
+	require.NoError(t, err)
+	return stats
+}
+
+func seed(hh *hhtest.Server) {
+	hh.SetCount("golang", 1, 306)
+	hh.SetCount("golang", 53, 4)
+	hh.SetCount("python", 1, 3181)
+	hh.SetCount("python", 53, 72)
+	hh.SetCount("kafka", 1, 150)
+}
+
+func query(t *testing.T, stats storage.Statistics, technology string, city int) storage.QueryResult {
+	for _, q := range stats.Manifest.Queries {
+		if q.Technology == technology && q.City == city {
+			return q
+		}
+	}
+	t.Fatalf("нет запроса %s/%d в манифесте", technology, city)
+	return storage.QueryResult{}
+}
+
+func TestCollect_HappyPath(t *testing.T) {
+	hh := hhtest.New()
+	defer hh.Close()
+	seed(hh)
+
+	cfg := setup(t, hh)
+	stats := collect(t, cfg)
+
+	assert.Equal(t, map[string]int{"Golang": 310, "Python": 3253, "Kafka": 150}, stats.Summary)
+	assert.Equal(t, 3713, stats.Total)
+	require.Len(t, stats.Cities, 2, "выключенный город не запрашивается")
+	assert.Zero(t, hh.Requests("golang", 237))
+
+	require.Len(t, stats.Groups, 1)
+	assert.Equal(t, 3713, stats.Groups[0].Total)
+
+	manifest := stats.Manifest
+	assert.Equal(t, 0, manifest.Failures)
+	assert.Equal(t, 1, manifest.Empty, "Kafka в Краснодаре — 0 вакансий без ошибок")
+	assert.Equal(t, map[string]int{"200": 8}, manifest.HTTPStatuses, "пустой ответ повторяется retry_count раз")
+	assert.Equal(t, hhparser.QueryEmpty, query(t, stats, "Kafka", 53).Status)
+	require.NotNil(t, manifest.Concurrency)
+	assert.False(t, manifest.Concurrency.Adaptive)
+	assert.Equal(t, 2, manifest.Concurrency.Final, "без adaptive — max_goroutines")
+
+	saved, err := storage.LoadLatest(cfg.Output.Directory)
+	require.NoError(t, err)
+	assert.Equal(t, stats.Summary, saved.Summary)
+	assert.Equal(t, manifest.RunID, saved.Manifest.RunID)
+	assert.FileExists(t, filepath.Join(cfg.Output.Directory, "stats_"+stats.Date.Format("2006-01-02")+".txt"))
+}
+
+func TestCollect_Faults(t *testing.T) {
+	hh := hhtest.New()
+	defer hh.Close()
+	seed(hh)
+
+	hh.Fail("golang", 1, hhtest.FaultTooManyRequests, hhtest.FaultServerError)
+	hh.Fail("golang", 53, hhtest.FaultServerError, hhtest.FaultServerError, hhtest.FaultServerError)
+	hh.Fail("python", 1, hhtest.FaultCaptcha, hhtest.FaultCaptcha, hhtest.FaultCaptcha)
+	hh.Fail("python", 53, hhtest.FaultMarkupChanged, hhtest.FaultMarkupChanged, hhtest.FaultMarkupChanged)
+
+	cfg := setup(t, hh)
+	stats := collect(t, cfg)
+
+	recovered := query(t, stats, "Golang", 1)
+	assert.Equal(t, hhparser.QueryOK, recovered.Status)
+	assert.Equal(t, 3, recovered.Attempts)
+	assert.Equal(t, []int{429, 500, 200}, recovered.HTTPStatuses)
+	assert.Equal(t, 306, stats.Summary["Golang"], "Краснодар не собран")
+
+	serverError := query(t, stats, "Golang", 53)
+	assert.Equal(t, hhparser.QueryFailed, serverError.Status)
+	assert.Contains(t, serverError.Error, "500")
+
+	captcha := query(t, stats, "Python", 1)
+	assert.Equal(t, hhparser.QueryFailed, captcha.Status)
+	assert.Equal(t, []int{403, 403, 403}, captcha.HTTPStatuses)
+	assert.Equal(t, 3, captcha.Blocks)
+	assert.Equal(t, hhparser.ErrCaptcha.Error(), captcha.Error)
+
+	markup := query(t, stats, "Python", 53)
+	assert.Equal(t, hhparser.QueryFailed, markup.Status, "изменённая вёрстка — ошибка, а не ноль вакансий")
+	assert.Equal(t, hhparser.ErrSearchCountsNotFound.Error(), markup.Error)
+
+	assert.Equal(t, 3, stats.Manifest.Failures)
+	assert.Equal(t, 1, stats.Manifest.Empty)
+	assert.Equal(t, 4, stats.Manifest.Blocks, "429 и три капчи")
+}
+
+func TestCollect_CacheSkipsBadPages(t *testing.T) {
+	hh := hhtest.New()
+	defer hh.Close()
+	seed(hh)
+
+	hh.Fail("golang", 1, hhtest.FaultMarkupChanged)
+
+	cfg := setup(t, hh)
+	cfg.Parser.Cache.Mode = "cache"
+	cfg.Parser.Cache.Dir = t.TempDir()
+	cfg.Parser.Cache.TTL = time.Hour
+
+	stats := collect(t, cfg)
+
+	recovered := query(t, stats, "Golang", 1)
+	assert.Equal(t, hhparser.QueryOK, recovered.Status, "страница без searchCounts не попала в кеш")
+	assert.Equal(t, 2, hh.Requests("golang", 1))
+	assert.Equal(t, 310, stats.Summary["Golang"])
+}
+
+func TestCollect_CircuitBreaker(t *testing.T) {
+	hh := hhtest.New()
+	defer hh.Close()
+	seed(hh)
+
+	for _, text := range []string{"golang", "python", "kafka"} {
+		for _, area := range []int{1, 53} {
+			hh.Fail(text, area, hhtest.FaultCaptcha, hhtest.FaultCaptcha, hhtest.FaultCaptcha)
+		}
+	}
+
+	cfg := setup(t, hh)
+	cfg.Parser.MaxGoroutines = 1
+	cfg.Parser.Throttle.BreakerThreshold = 2
+	cfg.Parser.Throttle.CooldownSeconds = 0
+	cfg.Parser.Throttle.MaxPauses = 1
+
+	_, err := run.Collect(context.Background(), cfg, nil)
+	require.ErrorContains(t, err, "все 6 запросов завершились ошибкой")
+
+	// Две капчи размыкают автомат, пробный запрос снова получает капчу — пауз больше max_pauses,
+	// остальные запросы к HH не отправляются
+	requests := 0
+	for _, text := range []string{"golang", "python", "kafka"} {
+		for _, area := range []int{1, 53} {
+			requests += hh.Requests(text, area)
+		}
+	}
+	assert.Equal(t, 3, requests)
+
+	_, err = storage.LoadLatest(cfg.Output.Directory)
+	assert.Error(t, err, "запуск без единого ответа не сохраняется")
+}
+
+func TestCollect_SlowResponses(t *testing.T) {
+	hh := hhtest.New()
+	defer hh.Close()
+	seed(hh)
+
+	hh.SetSlowDelay(time.Second)
+	hh.Fail("kafka", 1, hhtest.FaultSlow)
+
+	cfg := setup(t, hh)
+	cfg.Parser.Timeout = 100 * time.Millisecond
+
+	stats := collect(t, cfg)
+
+	slow := query(t, stats, "Kafka", 1)
+	assert.Equal(t, hhparser.QueryOK, slow.Status, "после таймаута повтор получает обычную страницу")
+	assert.Equal(t, 2, slow.Attempts)
+	assert.Equal(t, []int{200}, slow.HTTPStatuses)
+	assert.Equal(t, 150, stats.Summary["Kafka"])
+}
+
+func TestCollect_Postings(t *testing.T) {
+	hh := hhtest.New()
+	defer hh.Close()
+	seed(hh)
+
+	shared := hhtest.Vacancy{ID: "1", Name: "Backend Go/Python", Employer: "Авито", AreaID: "1", KeySkills: []string{"Go", "Python"}}
+	hh.SetVacancies("golang", 1, shared, hhtest.Vacancy{ID: "2", AreaID: "1"})
+	hh.SetVacancies("python", 1, shared)
+	hh.SetVacancies("python", 53, hhtest.Vacancy{ID: "3", AreaID: "53", KeySkills: []string{"Django"}})
+
+	cfg := setup(t, hh)
+	cfg.Vacancies.Enabled = true
+	cfg.Vacancies.URL = hh.APIURL()
+	require.NoError(t, cfg.Validate())
+
+	stats := collect(t, cfg)
+
+	report := stats.Manifest.Postings
+	require.NotNil(t, report)
+	assert.Equal(t, 3, report.Unique)
+	assert.Equal(t, stats.Total, report.Found, "found API совпадает с количеством на страницах поиска")
+	assert.Equal(t, 3, report.Details)
+	assert.Zero(t, report.Failures)
+
+	snapshot, err := storage.LoadPostings(cfg.Output.Directory, stats.Date)
+	require.NoError(t, err)
+	assert.Equal(t, stats.Manifest.RunID, snapshot.RunID)
+	require.Len(t, snapshot.Postings, 3)
+	assert.Equal(t, []string{"Golang", "Python"}, snapshot.Postings[0].Technologies)
+	assert.Equal(t, []string{"Go", "Python"}, snapshot.Postings[0].KeySkills)
+	assert.Equal(t, 53, snapshot.Postings[2].City)
+
+	dates, err := storage.ListRuns(cfg.Output.Directory)
+	require.NoError(t, err)
+	assert.Len(t, dates, 1, "снимок вакансий не считается запуском")
+
+	require.NotNil(t, stats.Unique)
+	assert.Equal(t, 3, stats.Unique.Total)
+	assert.Equal(t, 4, stats.Unique.Sum, "общая вакансия Golang и Python")
+	assert.Equal(t, 1, stats.Unique.Overlap.Counts[0][1])
+
+	require.NotEmpty(t, stats.Employers)
+	assert.Equal(t, "Golang", stats.Employers[0].Technology)
+	assert.Equal(t, "Авито", stats.Employers[0].Employers[0].Name)
+
+	for _, ext := range []string{".json", ".csv"} {
+		assert.FileExists(t, filepath.Join(cfg.Output.Directory, storage.SkillsDir, stats.Date.Format("2006-01-02")+ext))
+	}
+}
+
+func TestCollect_Lifecycle(t *testing.T) {
+	hh := hhtest.New()
+	defer hh.Close()
+	seed(hh)
+
+	hh.SetVacancies("golang", 1,
+		hhtest.Vacancy{ID: "1", Name: "Go developer", Employer: "Авито", AreaID: "1", KeySkills: []string{"Go"}},
+		hhtest.Vacancy{ID: "2", Name: "Backend", Employer: "Авито", AreaID: "1"})
+
+	cfg := setup(t, hh)
+	cfg.Vacancies.Enabled = true
+	cfg.Vacancies.URL = hh.APIURL()
+	require.NoError(t, cfg.Validate())
+
+	first := collect(t, cfg)
+	require.NotNil(t, first.Lifecycle)
+	assert.Equal(t, 2, first.Lifecycle.New)
+	assert.Equal(t, 2, first.Lifecycle.Active)
+
+	// Вакансия 2 снята с публикации, вакансия 1 осталась: её страница повторно не запрашивается
+	hh.SetVacancies("golang", 1,
+		hhtest.Vacancy{ID: "1", Name: "Go developer", Employer: "Авито", AreaID: "1", KeySkills: []string{"Go"}})
+
+	second := collect(t, cfg)
+	assert.Equal(t, 1, second.Manifest.Postings.Reused)
+	assert.Equal(t, 1, hh.DetailRequests("1"))
+	assert.Zero(t, second.Lifecycle.New)
+	assert.Equal(t, 1, second.Lifecycle.Closed)
+	assert.Equal(t, 1, second.Lifecycle.Active)
+
+	registry, err := storage.LoadRegistry(cfg.Output.Directory)
+	require.NoError(t, err)
+	require.Len(t, registry.Postings, 2)
+	assert.NotNil(t, registry.Postings["2"].ClosedAt)
+	assert.Equal(t, []string{"Go"}, registry.Postings["1"].KeySkills)
+}
+
+func TestCollect_Incremental(t *testing.T) {
+	hh := hhtest.New()
+	defer hh.Close()
+	seed(hh)
+
+	now := time.Now()
+	old := hhtest.Vacancy{ID: "1", Name: "Go developer", Employer: "Авито", AreaID: "1", PublishedAt: now.Add(-2 * time.Hour)}
+	gone := hhtest.Vacancy{ID: "2", Name: "Backend", Employer: "Авито", AreaID: "1", PublishedAt: now.Add(-time.Hour)}
+	hh.SetVacancies("golang", 1, old, gone)
+
+	cfg := setup(t, hh)
+	cfg.Vacancies.Enabled = true
+	cfg.Vacancies.URL = hh.APIURL()
+	cfg.Vacancies.Incremental = config.IncrementalConfig{Enabled: true, FullResyncDays: 7}
+	require.NoError(t, cfg.Validate())
+
+	first := collect(t, cfg)
+	assert.Nil(t, first.Manifest.Postings.Since, "первый запуск — полный обход")
+
+	// Вакансия 2 снята, появилась вакансия 3: инкрементальный сбор запрашивает только её
+	fresh := hhtest.Vacancy{ID: "3", Name: "Go lead", Employer: "Яндекс", AreaID: "1", PublishedAt: time.Now()}
+	hh.SetVacancies("golang", 1, old, fresh)
+
+	before := hh.TotalRequests()
+	second := collect(t, cfg)
+	report := second.Manifest.Postings
+	require.NotNil(t, report.Since)
+	assert.Equal(t, 1, report.Unique)
+	assert.Equal(t, 1, hh.DetailRequests("1"), "старая вакансия не запрашивается повторно")
+	assert.Equal(t, 1, hh.DetailRequests("2"), "пропавшая из выдачи вакансия не проверяется по id")
+	// 8 страниц поиска (пустой ответ повторяется), по странице API на запрос и страница новой вакансии
+	assert.Equal(t, 8+6+1, hh.TotalRequests()-before, "число запросов не зависит от числа открытых вакансий")
+	assert.True(t, second.Lifecycle.Approximate)
+	assert.Equal(t, 1, second.Lifecycle.New)
+	assert.Zero(t, second.Lifecycle.Closed, "без полного обхода вакансии не закрываются")
+	assert.Equal(t, 3, second.Unique.Total, "статистика по всем активным вакансиям реестра")
+
+	snapshot, err := storage.LoadPostings(cfg.Output.Directory, second.Date)
+	require.NoError(t, err)
+	assert.Len(t, snapshot.Postings, 3)
+
+	// Полный обход замечает снятую вакансию
+	cfg.Vacancies.Incremental.Enabled = false
+	third := collect(t, cfg)
+	assert.Nil(t, third.Manifest.Postings.Since)
+	assert.Equal(t, 1, third.Lifecycle.Closed)
+	assert.False(t, third.Lifecycle.Approximate)
+	assert.Equal(t, 2, third.Unique.Total)
+}
@@ -0,0 +1,57 @@
+package hhparser
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+var (
+	// ErrBlocked — hh.ru отказал в обслуживании: капча или слишком частые запросы
+	ErrBlocked         = errors.New("block: HH ограничил запросы")
+	ErrCaptcha         = fmt.Errorf("%w: капча", ErrBlocked)
+	ErrTooManyRequests = fmt.Errorf("%w: 429 Too Many Requests", ErrBlocked)
+)
+
+// Признаки страницы капчи hh.ru
+var captchaMarkers = [][]byte{
+	[]byte("/account/captcha"),
+	[]byte("captcha-input"),
+	[]byte("Проверка, что вы не робот"),
+	[]byte(`"captcha_required"`), // ошибка API
+}
+
+// detectBlock распознаёт ответ-блокировку. Страница с количеством вакансий
+// блокировкой не считается, даже если в ней встречается слово captcha.
+func detectBlock(status int, content []byte) error {
+	if status == http.StatusTooManyRequests {
+		return ErrTooManyRequests
+	}
+	if bytes.Contains(content, []byte("searchCounts")) {
+		return nil
+	}
+	for _, marker := range captchaMarkers {
+		if bytes.Contains(content, marker) {
+			return ErrCaptcha
+		}
+	}
+	return nil
+}
+
+// cacheable сообщает, можно ли сохранить ответ 200 в кеш: не блокировка
+// и либо страница поиска с количеством вакансий, либо JSON API
+func cacheable(content []byte) bool {
+	if detectBlock(http.StatusOK, content) != nil {
+		return false
+	}
+	return bytes.Contains(content, []byte("searchCounts")) || json.Valid(content)
+}
+
+func blockLabel(err error) string {
+	if errors.Is(err, ErrTooManyRequests) {
+		return "too_many_requests"
+	}
+	return "captcha"
+}
@@ -0,0 +1,73 @@
+package hhparser
+
+import (
+	"hhparser/internal/config"
+	"math/rand/v2"
+	"net/http"
+	"strings"
+)
+
+// Accept, который браузеры отправляют при переходе на страницу
+const (
+	acceptChrome  = "text/html,application/xhtml+xml,application/xml;q=0.9,image/avif,image/webp,image/apng,*/*;q=0.8,application/signed-exchange;v=b3;q=0.7"
+	acceptDefault = "text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8"
+)
+
+// headerTransport добавляет к запросам заголовки браузера: один User-Agent на весь сбор
+// с подходящим ему Accept, Accept-Language и произвольные заголовки из конфига.
+// User-Agent не меняется между запросами: cookies общие, и смена браузера
+// посреди сессии выглядит для hh.ru подозрительнее, чем один браузер.
+type headerTransport struct {
+	next           http.RoundTripper
+	headers        http.Header
+	userAgent      string
+	accept         string
+	acceptLanguage string
+}
+
+func newHeaderTransport(next http.RoundTripper, cfg config.RequestConfig) http.RoundTripper {
+	headers := make(http.Header, len(cfg.Headers))
+	for name, value := range cfg.Headers {
+		headers.Set(name, value)
+	}
+
+	t := &headerTransport{
+		next:           next,
+		headers:        headers,
+		acceptLanguage: cfg.AcceptLanguage,
+	}
+	if len(cfg.UserAgents) > 0 {
+		t.userAgent = cfg.UserAgents[rand.IntN(len(cfg.UserAgents))]
+		t.accept = acceptFor(t.userAgent)
+	}
+	return t
+}
+
+// acceptFor подбирает Accept под браузер из User-Agent
+func acceptFor(userAgent string) string {
+	if strings.Contains(userAgent, "Chrome/") && !strings.Contains(userAgent, "Firefox/") {
+		return acceptChrome
+	}
+	return acceptDefault
+}
+
+func (t *headerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	// RoundTripper не должен менять исходный запрос
+	req = req.Clone(req.Context())
+
+	for name, values := range t.headers {
+		req.Header[name] = values
+	}
+	if t.acceptLanguage != "" {
+		req.Header.Set("Accept-Language", t.acceptLanguage)
+	}
+	if t.userAgent != "" {
+		req.Header.Set("User-Agent", t.userAgent)
+		// Accept из конфига или самого запроса не перетирается
+		if req.Header.Get("Accept") == "" {
+			req.Header.Set("Accept", t.accept)
+		}
+	}
+
+	return t.next.RoundTrip(req)
+}
@@ -0,0 +1,110 @@
+package hhparser
+
+import (
+	"hhparser/internal/config"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewParserConfig_BrowserHeaders(t *testing.T) {
+	var got []http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = append(got, r.Header.Clone())
+		http.SetCookie(w, &http.Cookie{Name: "hhtoken", Value: "abc", Path: "/"})
+	}))
+	defer server.Close()
+
+	parserConfig, err := NewParserConfig(&config.Config{Parser: config.ParserConfig{
+		Timeout: time.Second,
+		Request: config.RequestConfig{
+			UserAgents:     []string{"Browser/1", "Browser/2"},
+			AcceptLanguage: "ru-RU,ru;q=0.9",
+			Cookies:        true,
+			Headers:        map[string]string{"referer": "https://hh.ru/"},
+		},
+	}})
+	require.NoError(t, err)
+
+	for range 3 {
+		req, err := http.NewRequest(http.MethodGet, server.URL+"/search/vacancy", nil)
+		require.NoError(t, err)
+		res, err := parserConfig.Client.Do(req)
+		require.NoError(t, err)
+		res.Body.Close()
+		assert.Empty(t, req.Header.Get("User-Agent"), "исходный запрос не меняется")
+	}
+
+	require.Len(t, got, 3)
+	userAgent := got[0].Get("User-Agent")
+	assert.Contains(t, []string{"Browser/1", "Browser/2"}, userAgent)
+	for _, header := range got {
+		assert.Equal(t, userAgent, header.Get("User-Agent"), "один User-Agent на весь сбор")
+		assert.Equal(t, acceptDefault, header.Get("Accept"))
+	}
+	assert.Equal(t, "ru-RU,ru;q=0.9", got[0].Get("Accept-Language"))
+	assert.Equal(t, "https://hh.ru/", got[0].Get("Referer"))
+
+	assert.Empty(t, got[0].Get("Cookie"))
+	assert.Equal(t, "hhtoken=abc", got[1].Get("Cookie"), "cookies сохраняются между запросами")
+}
+
+func TestNewParserConfig_NoCookies(t *testing.T) {
+	parserConfig, err := NewParserConfig(&config.Config{})
+	require.NoError(t, err)
+	assert.Nil(t, parserConfig.Client.Jar)
+}
+
+func TestAcceptFor(t *testing.T) {
+	tests := []struct {
+		name      string
+		userAgent string
+		expected  string
+	}{
+		{
+			name:      "Chrome",
+			userAgent: "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/131.0.0.0 Safari/537.36",
+			expected:  acceptChrome,
+		},
+		{
+			name:      "Safari",
+			userAgent: "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/18.1 Safari/605.1.15",
+			expected:  acceptDefault,
+		},
+		{
+			name:      "Firefox",
+			userAgent: "Mozilla/5.0 (X11; Linux x86_64; rv:133.0) Gecko/20100101 Firefox/133.0",
+			expected:  acceptDefault,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, acceptFor(tt.userAgent))
+		})
+	}
+}
+
+func TestHeaderTransport_KeepsAccept(t *testing.T) {
+	var got http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Clone()
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: newHeaderTransport(http.DefaultTransport, config.RequestConfig{
+		UserAgents: []string{"Browser/1"},
+	})}
+	req, err := http.NewRequest(http.MethodGet, server.URL, nil)
+	require.NoError(t, err)
+	req.Header.Set("Accept", "application/json")
+	res, err := client.Do(req)
+	require.NoError(t, err)
+	res.Body.Close()
+
+	assert.Equal(t, "application/json", got.Get("Accept"), "Accept запроса не перетирается")
+}
@@ -0,0 +1,43 @@
+package hhparser
+
+import (
+	"hhparser/internal/metrics"
+	"strconv"
+)
+
+var (
+	requestsTotal = metrics.NewCounterVec("hh_requests_total",
+		"Запросы к hh.ru по HTTP-статусу (error — ошибка соединения)", "status")
+	retriesTotal = metrics.NewCounterVec("hh_request_retries_total",
+		"Повторные запросы к hh.ru")
+	parseFailuresTotal = metrics.NewCounterVec("hh_parse_failures_total",
+		"Страницы, из которых не удалось извлечь количество вакансий")
+	requestDuration = metrics.NewHistogramVec("hh_request_duration_seconds",
+		"Время запроса к hh.ru, включая чтение ответа", metrics.DefaultBuckets)
+	runDuration = metrics.NewGaugeVec("hh_run_duration_seconds",
+		"Длительность последнего сбора")
+	lastRunTimestamp = metrics.NewGaugeVec("hh_last_run_timestamp_seconds",
+		"Время завершения последнего сбора (unix)")
+	blocksTotal = metrics.NewCounterVec("hh_blocks_total",
+		"Ответы-блокировки hh.ru: captcha или too_many_requests", "kind")
+	concurrencyLimit = metrics.NewGaugeVec("hh_concurrency_limit",
+		"Текущее число одновременных запросов к hh.ru")
+	circuitOpen = metrics.NewGaugeVec("hh_circuit_open",
+		"1 — сбор на паузе после блокировок hh.ru")
+)
+
+func init() {
+	metrics.Default.Register(requestsTotal)
+	metrics.Default.Register(retriesTotal)
+	metrics.Default.Register(parseFailuresTotal)
+	metrics.Default.Register(requestDuration)
+	metrics.Default.Register(runDuration)
+	metrics.Default.Register(lastRunTimestamp)
+	metrics.Default.Register(blocksTotal)
+	metrics.Default.Register(concurrencyLimit)
+	metrics.Default.Register(circuitOpen)
+}
+
+func statusLabel(code int) string {
+	return strconv.Itoa(code)
+}
@@ -1,11 +1,16 @@
 package hhparser
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"hhparser/internal/config"
+	"hhparser/internal/httpcache"
+	"hhparser/internal/proxy"
 	"io"
+	"log/slog"
 	"net/http"
+	"net/http/cookiejar"
 	"sync"
 	"time"
 )
@@ -15,6 +20,16 @@ var (
 	ErrCanNotReadData    = errors.New("io: Не могу прочитать данные с HH")
 	ErrVacancyNotInteger = errors.New("strconv: Не могу перевести количество вакансий в число")
 	ErrVacancyNotFind    = errors.New("getkeyWordByName: Вакансия не найдена в списке")
+	ErrUnexpectedStatus  = errors.New("http: Неожиданный статус ответа HH")
+	// На странице нет блока searchCounts — вероятно, изменилась вёрстка
+	ErrSearchCountsNotFound = errors.New("parse: На странице HH нет количества вакансий")
+)
+
+// Статусы запроса после сбора
+const (
+	QueryOK     = "ok"     // количество получено
+	QueryEmpty  = "empty"  // ответы разобраны, вакансий нет
+	QueryFailed = "failed" // все попытки закончились ошибкой
 )
 
 type ParserConfig struct {
@@ -25,16 +40,78 @@ type ParserConfig struct {
 	RetryCount         int
 	RateLimit          time.Duration
 	UrlSearchVacancies string
+	Throttle           config.ThrottleConfig
+	Adaptive           config.AdaptiveConfig
+	Vacancies          config.VacanciesConfig
+
+	// Вакансии, загруженные прошлыми запусками: их страницы повторно не запрашиваются
+	Known map[string]*Posting
+
+	// Если задано, CollectPostings запрашивает только вакансии, опубликованные
+	// или обновлённые после этого момента (date_from API hh.ru)
+	Since time.Time
+
+	// Клиент для запросов к hh.ru, nil — http.DefaultClient
+	Client *http.Client
+
+	// Необязательный обработчик событий прогресса
+	Progress ProgressFunc
+
+	// Отмена сбора: после неё запросы не отправляются, nil — без отмены
+	Context context.Context
 }
 
 type Vacancy struct {
 	Name       string
+	Key        string
 	SearchName string
+	Filters    config.FilterConfig
 	Count      int
 	NumCity    int
+
+	// Результат запроса для манифеста запуска
+	Status       string
+	Attempts     int
+	Blocks       int // ответов-блокировок
+	HTTPStatuses []int
+	Err          error
 }
 
-func NewParserConfig(cfg *config.Config) ParserConfig {
+func NewParserConfig(cfg *config.Config) (ParserConfig, error) {
+	var base http.RoundTripper
+	if cfg.Parser.Proxy.Enabled() {
+		pool, err := proxy.NewPool(cfg.Parser.Proxy)
+		if err != nil {
+			return ParserConfig{}, err
+		}
+		transport := proxy.NewTransport(pool, nil)
+		transport.Detect = func(status int, body []byte) bool {
+			return detectBlock(status, body) != nil
+		}
+		base = transport
+	}
+
+	// Кеш снаружи: ответ из кеша не расходует лимит прокси
+	transport, err := httpcache.New(base, httpcache.Options{
+		Mode: cfg.Parser.Cache.Mode,
+		Dir:  cfg.Parser.Cache.Dir,
+		TTL:  cfg.Parser.Cache.TTL,
+
+		Valid: cacheable,
+	})
+	if err != nil {
+		return ParserConfig{}, err
+	}
+
+	client := &http.Client{
+		Timeout:   cfg.Parser.Timeout,
+		Transport: newHeaderTransport(transport, cfg.Parser.Request),
+	}
+	if cfg.Parser.Request.Cookies {
+		// Ошибку cookiejar.New возвращает только при заданном PublicSuffixList
+		client.Jar, _ = cookiejar.New(nil)
+	}
+
 	return ParserConfig{
 		Cities:             cfg.Cities,
 		Technologies:       cfg.Technologies,
@@ -43,16 +120,38 @@ func NewParserConfig(cfg *config.Config) ParserConfig {
 		RetryCount:         cfg.Parser.RetryCount,
 		RateLimit:          cfg.Parser.RateLimit,
 		UrlSearchVacancies: cfg.Parser.UrlSearchVacancies,
-	}
+		Throttle:           cfg.Parser.Throttle,
+		Adaptive:           cfg.Parser.Adaptive,
+		Vacancies:          cfg.Vacancies,
+		Client:             client,
+	}, nil
 }
 
 func GetAllVacancy(cfg ParserConfig) []*Vacancy {
+	vacancies, _ := Collect(cfg)
+	return vacancies
+}
+
+// Collect собирает количество вакансий по всем запросам и возвращает,
+// как за сбор менялось число одновременных запросов
+func Collect(cfg ParserConfig) ([]*Vacancy, Concurrency) {
+	startTime := time.Now()
+	defer func() {
+		runDuration.Set(time.Since(startTime).Seconds())
+		lastRunTimestamp.Set(float64(time.Now().Unix()))
+	}()
+
 	var keyWords = creatingKeywordsFromConfig(cfg)
 
+	s := newSession(cfg)
+	s.progress = newProgress(cfg.Progress, len(keyWords))
+	slog.Info("collecting vacancies", "queries", len(keyWords), "goroutines", s.throttle.limit, "adaptive", s.throttle.adaptive)
+
 	var wg sync.WaitGroup
 	wg.Add(len(keyWords))
 
-	semaphore := make(chan struct{}, cfg.MaxGoroutines)
+	// Одновременных запросов не больше лимита throttle, горутин — не больше его верхней границы
+	semaphore := make(chan struct{}, s.throttle.maxLimit)
 
 	for _, keyWord := range keyWords {
 		semaphore <- struct{}{} // Занимаем слот (блокируется, если уже 4 горутины работают)
@@ -61,55 +160,203 @@ func GetAllVacancy(cfg ParserConfig) []*Vacancy {
 			defer wg.Done()
 			defer func() { <-semaphore }() // Освобождаем слот при завершении
 
-			kw.getCountVacancyFrom(cfg.UrlSearchVacancies, cfg.RetryCount)
+			kw.getCountVacancyFrom(s)
 		}(keyWord)
 	}
 
 	wg.Wait()
 
-	return keyWords
+	concurrency := s.throttle.concurrency()
+	slog.Info("collection finished",
+		"queries", len(keyWords),
+		"duration", time.Since(startTime),
+		"concurrency", concurrency.Final,
+		"concurrency_avg", concurrency.Average)
+
+	return keyWords, concurrency
 }
 
 func creatingKeywordsFromConfig(cfg ParserConfig) []*Vacancy {
 	var vacancies []*Vacancy
 	for _, city := range cfg.Cities {
 		for _, tech := range cfg.Technologies {
-			vacancies = append(vacancies, &Vacancy{
+			vacancy := &Vacancy{
 				Name:       tech.Name,
+				Key:        tech.Key(),
 				SearchName: tech.Search,
 				NumCity:    city.Code,
-			})
+			}
+			if tech.Filters != nil {
+				vacancy.Filters = *tech.Filters
+			}
+			vacancies = append(vacancies, vacancy)
 		}
 	}
 
 	return vacancies
 }
 
-func (vacancy *Vacancy) getCountVacancyFrom(url string, maxRetries int) {
-	for attempt := 1; attempt <= maxRetries; attempt++ {
-		var link = fmt.Sprintf(url, vacancy.SearchName, vacancy.NumCity)
+// session — общее для всех запросов одного сбора
+type session struct {
+	ctx      context.Context
+	client   *http.Client
+	url      string
+	retries  int
+	progress *progress
+	throttle *throttle
+}
 
-		res, err := http.Get(link)
-		if err != nil {
-			panic(ErrNoConnection)
+func newSession(cfg ParserConfig) *session {
+	s := &session{
+		ctx:      cfg.Context,
+		client:   cfg.Client,
+		url:      cfg.UrlSearchVacancies,
+		retries:  cfg.RetryCount,
+		throttle: newThrottle(cfg),
+	}
+	if s.client == nil {
+		s.client = http.DefaultClient
+	}
+	if s.ctx == nil {
+		s.ctx = context.Background()
+	}
+	return s
+}
+
+// get выполняет одну попытку GET: метрики, лог, распознавание блокировок и статуса.
+// Код ответа возвращается и при неудачной попытке, 0 — ответа не было.
+func (s *session) get(link string, logger *slog.Logger) ([]byte, int, outcome, error) {
+	req, err := http.NewRequestWithContext(s.ctx, http.MethodGet, link, nil)
+	if err != nil {
+		return nil, 0, outcomeFailure, err
+	}
+
+	requestStart := time.Now()
+	res, err := s.client.Do(req)
+	if err != nil {
+		requestsTotal.Inc("error")
+		logger.Error("hh request failed", "latency", time.Since(requestStart), "error", err)
+		return nil, 0, outcomeFailure, fmt.Errorf("%w: %v", ErrNoConnection, err)
+	}
+	content, err := io.ReadAll(res.Body)
+	res.Body.Close()
+	latency := time.Since(requestStart)
+	requestDuration.Observe(latency.Seconds())
+	requestsTotal.Inc(statusLabel(res.StatusCode))
+
+	logger = logger.With("status", res.StatusCode, "latency", latency, "bytes", len(content))
+	if err != nil {
+		logger.Error("failed to read hh response", "error", err)
+		return nil, res.StatusCode, outcomeFailure, ErrCanNotReadData
+	}
+
+	if err := detectBlock(res.StatusCode, content); err != nil {
+		blocksTotal.Inc(blockLabel(err))
+		logger.Warn("hh block page", "error", err)
+		return nil, res.StatusCode, outcomeBlocked, err
+	}
+
+	if res.StatusCode >= http.StatusBadRequest {
+		logger.Warn("unexpected hh status")
+		return nil, res.StatusCode, outcomeFailure, fmt.Errorf("%w: %d", ErrUnexpectedStatus, res.StatusCode)
+	}
+
+	logger.Debug("hh request")
+	return content, res.StatusCode, outcomeSuccess, nil
+}
+
+func (vacancy *Vacancy) getCountVacancyFrom(s *session) {
+	logger := slog.With("technology", vacancy.Key, "city", vacancy.NumCity)
+	event := ProgressEvent{Technology: vacancy.Key, City: vacancy.NumCity}
+
+	finish := func(status string, err error) {
+		vacancy.Status, vacancy.Err = status, err
+
+		event.Type, event.Count, event.Err = ProgressSucceeded, vacancy.Count, err
+		if status == QueryFailed {
+			event.Type = ProgressFailed
 		}
-		content, err := io.ReadAll(res.Body)
-		res.Body.Close()
+		s.progress.emit(event)
+	}
+
+	link, err := vacancy.Filters.Apply(fmt.Sprintf(s.url, vacancy.SearchName, vacancy.NumCity))
+	if err != nil {
+		logger.Error("invalid search url", "error", err)
+		finish(QueryFailed, err)
+		return
+	}
+
+	var lastErr error
+	for attempt := 1; attempt <= s.retries; attempt++ {
+		if err := s.ctx.Err(); err != nil {
+			lastErr = err
+			break
+		}
+		ticket, err := s.throttle.acquire()
 		if err != nil {
-			panic(ErrCanNotReadData)
+			lastErr = err
+			break
+		}
+
+		vacancy.Attempts = attempt
+		event.Attempt = attempt
+		if attempt > 1 {
+			retriesTotal.Inc()
+			event.Type = ProgressRetried
+		} else {
+			event.Type = ProgressStarted
 		}
+		s.progress.emit(event)
 
-		var countVac, _ = injectSearchCounts(string(content))
-		if countVac > 0 {
-			vacancy.Count = countVac
+		count, result, err := vacancy.fetch(s, link, logger.With("attempt", attempt))
+		s.throttle.release(ticket, result)
+		lastErr = err
+
+		if count > 0 {
+			vacancy.Count = count
+			finish(QueryOK, nil)
 			return
 		}
 	}
+
+	logger.Warn("no vacancies after retries", "attempts", vacancy.Attempts, "error", lastErr)
+
+	// Ноль без ошибок — поиск честно ничего не нашёл
+	if lastErr != nil {
+		finish(QueryFailed, lastErr)
+		return
+	}
+	finish(QueryEmpty, nil)
+}
+
+// fetch выполняет одну попытку запроса и возвращает количество вакансий
+func (vacancy *Vacancy) fetch(s *session, link string, logger *slog.Logger) (int, outcome, error) {
+	content, status, result, err := s.get(link, logger)
+	if status != 0 {
+		vacancy.HTTPStatuses = append(vacancy.HTTPStatuses, status)
+	}
+	if errors.Is(err, ErrBlocked) {
+		vacancy.Blocks++
+	}
+	if err != nil {
+		return 0, result, err
+	}
+
+	count, err := injectSearchCounts(string(content))
+	if err != nil {
+		parseFailuresTotal.Inc()
+		logger.Warn("vacancy count not found", "status", status, "error", err)
+		return 0, outcomeFailure, err
+	}
+
+	logger.Debug("vacancy count", "count", count)
+	return count, outcomeSuccess, nil
 }
 
-func GetkeyWordByNameAndCountry(vacancies []*Vacancy, name string, country int) *Vacancy {
+// GetkeyWordByNameAndCountry ищет запрос по ключу технологии (см. config.TechnologyConfig.Key) и коду города
+func GetkeyWordByNameAndCountry(vacancies []*Vacancy, key string, country int) *Vacancy {
 	for _, vacancy := range vacancies {
-		if vacancy.Name == name && vacancy.NumCity == country {
+		if vacancy.Key == key && vacancy.NumCity == country {
 			return vacancy
 		}
 	}
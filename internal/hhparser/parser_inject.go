@@ -6,7 +6,12 @@ import (
 )
 
 func injectSearchCounts(content string) (int, error) {
-	var start = strings.Index(content, "searchCounts") + 15
+	var index = strings.Index(content, "searchCounts")
+	if index < 0 {
+		return 0, ErrSearchCountsNotFound
+	}
+
+	var start = index + 15
 	var bracket uint32 = 1
 
 	for end := start; end < len(content); end++ {
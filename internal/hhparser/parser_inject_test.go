@@ -1,6 +1,7 @@
 package hhparser
 
 import (
+	"errors"
 	"testing"
 )
 
@@ -62,3 +63,41 @@ func TestInjectCount(t *testing.T) {
 		})
 	}
 }
+
+func TestInjectSearchCounts(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected int
+		wantErr  error
+	}{
+		{
+			name:     "search page",
+			input:    `<template>{"searchCounts":{"value":306,"isLoaded":true},"other":{}}</template>`,
+			expected: 306,
+		},
+		{
+			name:    "markup without searchCounts",
+			input:   `<template>{"vacancySearch":{"found":306}}</template>`,
+			wantErr: ErrSearchCountsNotFound,
+		},
+		{
+			name:    "non-integer value",
+			input:   `{"searchCounts":{"value":"n/a","isLoaded":true}}`,
+			wantErr: ErrVacancyNotInteger,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			count, err := injectSearchCounts(tt.input)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("injectSearchCounts() error = %v, want %v", err, tt.wantErr)
+				return
+			}
+			if count != tt.expected {
+				t.Errorf("injectSearchCounts() = %v, want %v", count, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,398 @@
+package hhparser
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+var ErrInvalidResponse = errors.New("parse: Не могу разобрать ответ API HH")
+
+// Posting — объявление о вакансии hh.ru
+type Posting struct {
+	ID          string    `json:"id"`
+	Name        string    `json:"name"`
+	URL         string    `json:"url,omitempty"`
+	Employer    Employer  `json:"employer"`
+	Salary      *Salary   `json:"salary,omitempty"`
+	Area        Area      `json:"area"`
+	Experience  string    `json:"experience,omitempty"`
+	Schedule    string    `json:"schedule,omitempty"`
+	Employment  string    `json:"employment,omitempty"`
+	PublishedAt time.Time `json:"publishedAt"`
+	KeySkills   []string  `json:"keySkills,omitempty"`
+
+	// По каким запросам найдена: ключи технологий (см. config.TechnologyConfig.Key)
+	// в порядке конфига и код первого по конфигу города, где она встретилась
+	Technologies []string `json:"technologies"`
+	City         int      `json:"city"`
+
+	// Загружена страница вакансии, KeySkills заполнены
+	Detailed bool `json:"detailed"`
+}
+
+type Employer struct {
+	ID   string `json:"id,omitempty"`
+	Name string `json:"name"`
+}
+
+// Salary — вилка как в объявлении, 0 — граница не указана
+type Salary struct {
+	From     int    `json:"from,omitempty"`
+	To       int    `json:"to,omitempty"`
+	Currency string `json:"currency"`
+	Gross    bool   `json:"gross"`
+}
+
+type Area struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// PostingsReport — итог сбора вакансий для манифеста запуска
+type PostingsReport struct {
+	Queries   int `json:"queries"`
+	Pages     int `json:"pages"`
+	Found     int `json:"found"`  // сумма found по запросам, с пересечениями
+	Unique    int `json:"unique"` // вакансий после дедупликации по ID
+	Details   int `json:"details"`
+	Reused    int `json:"reused"`    // навыки взяты из уже известных вакансий без запроса
+	Failures  int `json:"failures"`  // списков и страниц вакансий, не загруженных после всех попыток
+	Truncated int `json:"truncated"` // запросов, упёршихся в max_pages
+
+	// Запросы, выдача которых обойдена не полностью: из-за ошибки или max_pages
+	Incomplete []PostingsQuery `json:"incomplete,omitempty"`
+
+	// Инкрементальный сбор: только вакансии, опубликованные или обновлённые после Since
+	Since *time.Time `json:"since,omitempty"`
+}
+
+type PostingsQuery struct {
+	Technology string `json:"technology"`
+	City       int    `json:"city"`
+	Truncated  bool   `json:"truncated,omitempty"` // упёрся в max_pages, иначе — ошибка
+}
+
+// Ответы API hh.ru
+type (
+	apiSearchPage struct {
+		Items []apiVacancy `json:"items"`
+		Found int          `json:"found"`
+		Pages int          `json:"pages"`
+	}
+
+	apiVacancy struct {
+		ID           string `json:"id"`
+		Name         string `json:"name"`
+		AlternateURL string `json:"alternate_url"`
+		Employer     *struct {
+			ID   string `json:"id"`
+			Name string `json:"name"`
+		} `json:"employer"`
+		Salary *struct {
+			From     *int   `json:"from"`
+			To       *int   `json:"to"`
+			Currency string `json:"currency"`
+			Gross    *bool  `json:"gross"`
+		} `json:"salary"`
+		Area        Area          `json:"area"`
+		Experience  *apiRef       `json:"experience"`
+		Schedule    *apiRef       `json:"schedule"`
+		Employment  *apiRef       `json:"employment"`
+		PublishedAt string        `json:"published_at"`
+		KeySkills   []apiKeySkill `json:"key_skills"`
+	}
+
+	apiRef struct {
+		ID string `json:"id"`
+	}
+
+	apiKeySkill struct {
+		Name string `json:"name"`
+	}
+)
+
+// Формат дат API hh.ru: 2026-02-15T09:00:00+0300
+const apiTimeLayout = "2006-01-02T15:04:05-0700"
+
+func (v apiVacancy) posting() *Posting {
+	p := &Posting{
+		ID:         v.ID,
+		Name:       v.Name,
+		URL:        v.AlternateURL,
+		Area:       v.Area,
+		Experience: v.Experience.id(),
+		Schedule:   v.Schedule.id(),
+		Employment: v.Employment.id(),
+	}
+	if v.Employer != nil {
+		p.Employer = Employer{ID: v.Employer.ID, Name: v.Employer.Name}
+	}
+	if v.Salary != nil {
+		p.Salary = &Salary{Currency: v.Salary.Currency}
+		if v.Salary.From != nil {
+			p.Salary.From = *v.Salary.From
+		}
+		if v.Salary.To != nil {
+			p.Salary.To = *v.Salary.To
+		}
+		if v.Salary.Gross != nil {
+			p.Salary.Gross = *v.Salary.Gross
+		}
+	}
+	if published, err := time.Parse(apiTimeLayout, v.PublishedAt); err == nil {
+		p.PublishedAt = published
+	}
+	return p
+}
+
+func (r *apiRef) id() string {
+	if r == nil {
+		return ""
+	}
+	return r.ID
+}
+
+// crawl — состояние одного сбора вакансий
+type crawl struct {
+	*session
+	cfg ParserConfig
+
+	// Порядок технологий и городов в конфиге
+	techOrder map[string]int
+	cityOrder map[int]int
+
+	mu     sync.Mutex
+	byID   map[string]*Posting
+	report PostingsReport
+}
+
+// CollectPostings обходит постранично выдачу API hh.ru по каждой технологии и городу,
+// убирает повторы по ID и, если включено vacancies.details, загружает страницу каждой
+// вакансии ради ключевых навыков. Запросы идут через тот же клиент и throttle,
+// что и сбор количества: действуют rate_limit_ms, прокси и реакция на блокировки.
+func CollectPostings(cfg ParserConfig) ([]*Posting, PostingsReport) {
+	startTime := time.Now()
+	queries := creatingKeywordsFromConfig(cfg)
+
+	c := &crawl{
+		session: newSession(cfg),
+		cfg:     cfg,
+		byID:    make(map[string]*Posting),
+
+		techOrder: make(map[string]int),
+		cityOrder: make(map[int]int),
+	}
+	for i, tech := range cfg.Technologies {
+		c.techOrder[tech.Key()] = i
+	}
+	for i, city := range cfg.Cities {
+		c.cityOrder[city.Code] = i
+	}
+	c.report.Queries = len(queries)
+	if !cfg.Since.IsZero() {
+		since := cfg.Since
+		c.report.Since = &since
+	}
+	slog.Info("collecting postings", "queries", len(queries), "details", cfg.Vacancies.Details, "since", c.report.Since)
+
+	c.parallel(len(queries), func(i int) { c.search(queries[i]) })
+
+	postings := make([]*Posting, 0, len(c.byID))
+	for _, posting := range c.byID {
+		slices.SortFunc(posting.Technologies, func(a, b string) int { return c.techOrder[a] - c.techOrder[b] })
+		postings = append(postings, posting)
+	}
+	slices.SortFunc(postings, func(a, b *Posting) int { return strings.Compare(a.ID, b.ID) })
+	c.report.Unique = len(postings)
+	slices.SortFunc(c.report.Incomplete, func(a, b PostingsQuery) int {
+		if a.Technology != b.Technology {
+			return c.techOrder[a.Technology] - c.techOrder[b.Technology]
+		}
+		return c.cityOrder[a.City] - c.cityOrder[b.City]
+	})
+
+	if cfg.Vacancies.Details {
+		c.parallel(len(postings), func(i int) { c.detail(postings[i]) })
+	}
+
+	slog.Info("postings collected",
+		"unique", c.report.Unique,
+		"found", c.report.Found,
+		"details", c.report.Details,
+		"reused", c.report.Reused,
+		"failures", c.report.Failures,
+		"duration", time.Since(startTime))
+
+	return postings, c.report
+}
+
+// parallel выполняет fn для 0..n-1, не больше верхней границы throttle одновременно
+func (c *crawl) parallel(n int, fn func(i int)) {
+	var wg sync.WaitGroup
+	semaphore := make(chan struct{}, c.throttle.maxLimit)
+
+	for i := range n {
+		semaphore <- struct{}{}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-semaphore }()
+			fn(i)
+		}()
+	}
+	wg.Wait()
+}
+
+// incomplete отмечает запрос, выдача которого обойдена не полностью
+func (c *crawl) incomplete(query *Vacancy, truncated bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.report.Incomplete = append(c.report.Incomplete,
+		PostingsQuery{Technology: query.Key, City: query.NumCity, Truncated: truncated})
+}
+
+// search обходит страницы выдачи одного запроса
+func (c *crawl) search(query *Vacancy) {
+	logger := slog.With("technology", query.Key, "city", query.NumCity)
+	vacancies := c.cfg.Vacancies
+
+	var since string
+	if !c.cfg.Since.IsZero() {
+		since = "&date_from=" + url.QueryEscape(c.cfg.Since.Format(apiTimeLayout))
+	}
+
+	for page := range vacancies.MaxPages {
+		link, err := query.Filters.ApplyAPI(fmt.Sprintf("%s?text=%s&area=%d&page=%d&per_page=%d%s",
+			vacancies.URL, query.SearchName, query.NumCity, page, vacancies.PerPage, since))
+		if err != nil {
+			logger.Error("invalid vacancies url", "error", err)
+			c.fail()
+			c.incomplete(query, false)
+			return
+		}
+
+		var result apiSearchPage
+		if err := c.getJSON(link, &result, logger.With("page", page)); err != nil {
+			logger.Warn("vacancies page not loaded", "page", page, "error", err)
+			c.fail()
+			c.incomplete(query, false)
+			return
+		}
+
+		c.add(query, result, page == 0)
+		if page+1 >= result.Pages {
+			return
+		}
+	}
+
+	logger.Warn("vacancies truncated by max_pages", "max_pages", vacancies.MaxPages)
+	c.mu.Lock()
+	c.report.Truncated++
+	c.mu.Unlock()
+	c.incomplete(query, true)
+}
+
+// add учитывает страницу выдачи: новая вакансия добавляется, у найденной ранее
+// дополняется список технологий
+func (c *crawl) add(query *Vacancy, page apiSearchPage, first bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.report.Pages++
+	if first {
+		c.report.Found += page.Found
+	}
+
+	for _, item := range page.Items {
+		posting, ok := c.byID[item.ID]
+		if !ok {
+			posting = item.posting()
+			posting.City = query.NumCity
+			c.byID[item.ID] = posting
+		}
+		if c.cityOrder[query.NumCity] < c.cityOrder[posting.City] {
+			posting.City = query.NumCity
+		}
+		if !slices.Contains(posting.Technologies, query.Key) {
+			posting.Technologies = append(posting.Technologies, query.Key)
+		}
+	}
+}
+
+// detail дополняет вакансию данными её страницы в API. Навыки уже известной
+// вакансии (ParserConfig.Known) берутся без запроса, если с тех пор она не обновлялась.
+func (c *crawl) detail(posting *Posting) {
+	if known := c.cfg.Known[posting.ID]; known != nil && known.Detailed && known.PublishedAt.Equal(posting.PublishedAt) {
+		posting.KeySkills = known.KeySkills
+		posting.Detailed = true
+
+		c.mu.Lock()
+		c.report.Reused++
+		c.mu.Unlock()
+		return
+	}
+
+	logger := slog.With("vacancy", posting.ID)
+
+	var result apiVacancy
+	if err := c.getJSON(c.cfg.Vacancies.URL+"/"+posting.ID, &result, logger); err != nil {
+		logger.Warn("vacancy details not loaded", "error", err)
+		c.fail()
+		return
+	}
+
+	posting.KeySkills = make([]string, 0, len(result.KeySkills))
+	for _, skill := range result.KeySkills {
+		posting.KeySkills = append(posting.KeySkills, skill.Name)
+	}
+	posting.Detailed = true
+
+	c.mu.Lock()
+	c.report.Details++
+	c.mu.Unlock()
+}
+
+func (c *crawl) fail() {
+	c.mu.Lock()
+	c.report.Failures++
+	c.mu.Unlock()
+}
+
+// getJSON загружает ответ API с повторами через throttle и разбирает его в v
+func (s *session) getJSON(link string, v any, logger *slog.Logger) error {
+	var lastErr error
+	for attempt := 1; attempt <= s.retries; attempt++ {
+		if err := s.ctx.Err(); err != nil {
+			return err
+		}
+		ticket, err := s.throttle.acquire()
+		if err != nil {
+			return err
+		}
+		if attempt > 1 {
+			retriesTotal.Inc()
+		}
+
+		content, _, result, err := s.get(link, logger.With("attempt", attempt))
+		if err == nil {
+			if err = json.Unmarshal(content, v); err != nil {
+				parseFailuresTotal.Inc()
+				result, err = outcomeFailure, fmt.Errorf("%w: %v", ErrInvalidResponse, err)
+			}
+		}
+		s.throttle.release(ticket, result)
+
+		if err == nil {
+			return nil
+		}
+		lastErr = err
+	}
+	return lastErr
+}
@@ -0,0 +1,182 @@
+package hhparser
+
+import (
+	"context"
+	"hhparser/internal/config"
+	"hhparser/internal/hhtest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func postingsConfig(hh *hhtest.Server) ParserConfig {
+	return ParserConfig{
+		Cities: []config.CityConfig{{Name: "MOSCOW", Code: 1}, {Name: "KRASNODAR", Code: 53}},
+		Technologies: []config.TechnologyConfig{
+			{Name: "Java", Search: "java"},
+			{Name: "Spring", Search: "spring"},
+		},
+		MaxGoroutines: 2,
+		RetryCount:    2,
+		Vacancies: config.VacanciesConfig{
+			URL:      hh.APIURL(),
+			PerPage:  2,
+			MaxPages: 10,
+			Details:  true,
+		},
+	}
+}
+
+func TestCollectPostings(t *testing.T) {
+	published := time.Date(2026, 2, 14, 10, 30, 0, 0, time.FixedZone("MSK", 3*60*60))
+	backend := hhtest.Vacancy{
+		ID:          "100",
+		Name:        "Java-разработчик",
+		EmployerID:  "1740",
+		Employer:    "Яндекс",
+		SalaryFrom:  250000,
+		Currency:    "RUR",
+		AreaID:      "1",
+		AreaName:    "Москва",
+		Experience:  "between3And6",
+		Schedule:    "remote",
+		Employment:  "full",
+		PublishedAt: published,
+		KeySkills:   []string{"Java", "Spring Boot", "PostgreSQL"},
+	}
+
+	hh := hhtest.New()
+	defer hh.Close()
+	hh.SetVacancies("java", 1, backend, hhtest.Vacancy{ID: "101", AreaID: "1"}, hhtest.Vacancy{ID: "102", AreaID: "1"})
+	hh.SetVacancies("spring", 1, backend)
+	hh.SetVacancies("java", 53, hhtest.Vacancy{ID: "200", AreaID: "53"})
+
+	postings, report := CollectPostings(postingsConfig(hh))
+
+	require.Len(t, postings, 4)
+	assert.Equal(t, []string{"100", "101", "102", "200"}, []string{postings[0].ID, postings[1].ID, postings[2].ID, postings[3].ID})
+
+	got := postings[0]
+	assert.Equal(t, &Posting{
+		ID:           "100",
+		Name:         "Java-разработчик",
+		URL:          "https://hh.ru/vacancy/100",
+		Employer:     Employer{ID: "1740", Name: "Яндекс"},
+		Salary:       &Salary{From: 250000, Currency: "RUR"},
+		Area:         Area{ID: "1", Name: "Москва"},
+		Experience:   "between3And6",
+		Schedule:     "remote",
+		Employment:   "full",
+		PublishedAt:  got.PublishedAt,
+		KeySkills:    []string{"Java", "Spring Boot", "PostgreSQL"},
+		Technologies: []string{"Java", "Spring"},
+		City:         1,
+		Detailed:     true,
+	}, got)
+	assert.True(t, published.Equal(got.PublishedAt))
+	assert.Nil(t, postings[1].Salary)
+	assert.Empty(t, postings[1].KeySkills)
+
+	assert.Equal(t, PostingsReport{
+		Queries: 4,
+		Pages:   5, // java/1 — три вакансии на двух страницах, остальные по одной, spring/53 пустая
+		Found:   5,
+		Unique:  4,
+		Details: 4,
+	}, report)
+	assert.Equal(t, 1, hh.DetailRequests("100"), "повторы по ID загружаются один раз")
+}
+
+func TestCollectPostings_Failures(t *testing.T) {
+	hh := hhtest.New()
+	defer hh.Close()
+	hh.SetVacancies("java", 1, hhtest.Vacancy{ID: "100"}, hhtest.Vacancy{ID: "101"}, hhtest.Vacancy{ID: "102"})
+	hh.Fail("java", 1, hhtest.FaultServerError)
+	hh.Fail("spring", 1, hhtest.FaultServerError, hhtest.FaultServerError)
+
+	cfg := postingsConfig(hh)
+	cfg.Vacancies.MaxPages = 1
+	cfg.Vacancies.Details = false
+
+	postings, report := CollectPostings(cfg)
+
+	require.Len(t, postings, 2, "первая страница java/1 после повтора, вторая за max_pages")
+	assert.False(t, postings[0].Detailed)
+	assert.Equal(t, 1, report.Failures, "spring/1 не загружен за retry_count попыток")
+	assert.Equal(t, 1, report.Truncated)
+	assert.Zero(t, report.Details)
+	assert.Equal(t, []PostingsQuery{{"Java", 1, true}, {"Spring", 1, false}}, report.Incomplete)
+}
+
+func TestCollectPostings_KnownSkipsDetails(t *testing.T) {
+	hh := hhtest.New()
+	defer hh.Close()
+	hh.SetVacancies("java", 1, hhtest.Vacancy{ID: "100", KeySkills: []string{"Kotlin"}}, hhtest.Vacancy{ID: "101"})
+
+	cfg := postingsConfig(hh)
+	cfg.Known = map[string]*Posting{
+		"100": {ID: "100", KeySkills: []string{"Java"}, Detailed: true},
+		"101": {ID: "101"}, // навыки в прошлый раз не загрузились
+	}
+
+	postings, report := CollectPostings(cfg)
+
+	require.Len(t, postings, 2)
+	assert.Equal(t, []string{"Java"}, postings[0].KeySkills, "навыки известной вакансии не перезапрашиваются")
+	assert.Zero(t, hh.DetailRequests("100"))
+	assert.Equal(t, 1, hh.DetailRequests("101"))
+	assert.Equal(t, 1, report.Reused)
+	assert.Equal(t, 1, report.Details)
+}
+
+func TestCollectPostings_Since(t *testing.T) {
+	hh := hhtest.New()
+	defer hh.Close()
+
+	checkpoint := time.Date(2026, 3, 1, 12, 0, 0, 0, time.FixedZone("MSK", 3*3600))
+	hh.SetVacancies("java", 1,
+		hhtest.Vacancy{ID: "100", PublishedAt: checkpoint.Add(-time.Hour)},
+		hhtest.Vacancy{ID: "101", PublishedAt: checkpoint.Add(time.Hour), KeySkills: []string{"Kotlin"}},
+		hhtest.Vacancy{ID: "102", PublishedAt: checkpoint.Add(2 * time.Hour)})
+
+	cfg := postingsConfig(hh)
+	cfg.Since = checkpoint
+	cfg.Known = map[string]*Posting{
+		"101": {ID: "101", KeySkills: []string{"Java"}, Detailed: true, PublishedAt: checkpoint.Add(-24 * time.Hour)},
+	}
+
+	postings, report := CollectPostings(cfg)
+
+	require.Len(t, postings, 2, "вакансия до контрольной точки не запрашивается")
+	assert.Equal(t, "101", postings[0].ID)
+	assert.Equal(t, []string{"Kotlin"}, postings[0].KeySkills, "обновлённая вакансия загружается заново")
+	assert.Equal(t, 2, report.Details)
+	assert.Zero(t, report.Reused)
+	require.NotNil(t, report.Since)
+	assert.True(t, checkpoint.Equal(*report.Since))
+}
+
+func TestCollect_Cancelled(t *testing.T) {
+	hh := hhtest.New()
+	defer hh.Close()
+	hh.SetCount("java", 1, 10)
+	hh.SetVacancies("java", 1, hhtest.Vacancy{ID: "100"})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	cfg := postingsConfig(hh)
+	cfg.UrlSearchVacancies = hh.SearchURL()
+	cfg.Context = ctx
+
+	vacancies, _ := Collect(cfg)
+	require.Len(t, vacancies, 4)
+	assert.Equal(t, QueryFailed, vacancies[0].Status)
+	assert.ErrorIs(t, vacancies[0].Err, context.Canceled)
+
+	postings, _ := CollectPostings(cfg)
+	assert.Empty(t, postings)
+	assert.Zero(t, hh.Requests("java", 1), "после отмены запросы не отправляются")
+}
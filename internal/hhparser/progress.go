@@ -0,0 +1,56 @@
+package hhparser
+
+import "sync"
+
+const (
+	ProgressStarted   = "started"
+	ProgressRetried   = "retried"
+	ProgressSucceeded = "succeeded"
+	ProgressFailed    = "failed"
+)
+
+// ProgressEvent — событие по одному запросу (технология × город) во время сбора
+type ProgressEvent struct {
+	Type       string
+	Technology string // ключ технологии, см. config.TechnologyConfig.Key
+	City       int
+	Attempt    int
+	Count      int   // для succeeded
+	Err        error // для failed
+
+	// Завершённые (succeeded и failed) и все запросы сбора
+	Done  int
+	Total int
+}
+
+// ProgressFunc получает события сбора. Вызовы сериализуются, поэтому
+// обработчику не нужна своя синхронизация, но он не должен блокироваться надолго.
+type ProgressFunc func(ProgressEvent)
+
+type progress struct {
+	mu     sync.Mutex
+	report ProgressFunc
+	done   int
+	total  int
+}
+
+func newProgress(report ProgressFunc, total int) *progress {
+	return &progress{report: report, total: total}
+}
+
+func (p *progress) emit(event ProgressEvent) {
+	if p == nil || p.report == nil {
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if event.Type == ProgressSucceeded || event.Type == ProgressFailed {
+		p.done++
+	}
+	event.Done = p.done
+	event.Total = p.total
+
+	p.report(event)
+}
@@ -0,0 +1,66 @@
+package hhparser
+
+import (
+	"fmt"
+	"hhparser/internal/config"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetAllVacancy_Progress(t *testing.T) {
+	var golangRequests atomic.Int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("text") {
+		case "golang":
+			// Первый ответ без количества, повтор успешный
+			if golangRequests.Add(1) == 1 {
+				fmt.Fprint(w, `<html></html>`)
+				return
+			}
+			fmt.Fprint(w, `{"searchCounts":{"value":42,"isLoaded":true}}`)
+		default:
+			fmt.Fprint(w, `{"searchCounts":{"value":"n/a",}}`)
+		}
+	}))
+	defer server.Close()
+
+	var events []ProgressEvent
+	vacancies := GetAllVacancy(ParserConfig{
+		Cities: []config.CityConfig{{Name: "MOSCOW", Code: 1}},
+		Technologies: []config.TechnologyConfig{
+			{Name: "Golang", Search: "golang"},
+			{Name: "Php", Search: "php"},
+		},
+		MaxGoroutines:      1,
+		RetryCount:         2,
+		UrlSearchVacancies: server.URL + "/search?text=%s&area=%d",
+		Progress:           func(event ProgressEvent) { events = append(events, event) },
+	})
+
+	require.Len(t, vacancies, 2)
+	assert.Equal(t, 42, vacancies[0].Count)
+
+	var types []string
+	for _, event := range events {
+		types = append(types, event.Type)
+		assert.Equal(t, 2, event.Total)
+	}
+	assert.Equal(t, []string{
+		ProgressStarted, ProgressRetried, ProgressSucceeded,
+		ProgressStarted, ProgressRetried, ProgressFailed,
+	}, types)
+
+	succeeded, failed := events[2], events[5]
+	assert.Equal(t, ProgressEvent{
+		Type: ProgressSucceeded, Technology: "Golang", City: 1, Attempt: 2, Count: 42, Done: 1, Total: 2,
+	}, succeeded)
+	assert.Equal(t, "Php", failed.Technology)
+	assert.ErrorIs(t, failed.Err, ErrVacancyNotInteger)
+	assert.Equal(t, 2, failed.Done)
+}
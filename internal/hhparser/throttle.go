@@ -0,0 +1,309 @@
+package hhparser
+
+import (
+	"errors"
+	"log/slog"
+	"math"
+	"sync"
+	"time"
+)
+
+var ErrCircuitOpen = errors.New("block: сбор остановлен после повторных блокировок HH")
+
+// Исход попытки для throttle
+type outcome int
+
+const (
+	outcomeSuccess outcome = iota
+	outcomeFailure         // ошибка, не связанная с блокировкой
+	outcomeBlocked
+)
+
+const (
+	breakerClosed = iota
+	breakerOpen
+	breakerHalfOpen
+	breakerTripped // пауз больше max_pauses, запросы больше не отправляются
+)
+
+// Concurrency — как менялось число одновременных запросов за сбор, для манифеста
+type Concurrency struct {
+	Adaptive  bool    `json:"adaptive"`
+	Min       int     `json:"min"`
+	Max       int     `json:"max"`
+	Initial   int     `json:"initial"`
+	Final     int     `json:"final"`
+	Peak      int     `json:"peak"`
+	Average   float64 `json:"average"` // среднее значение лимита по запросам
+	Increases int     `json:"increases"`
+	Decreases int     `json:"decreases"`
+}
+
+// ticket выдаётся acquire и возвращается в release
+type ticket struct {
+	seq        uint64
+	generation uint64
+	start      time.Time
+}
+
+// throttle подстраивает параллельность и задержку под реакцию hh.ru по схеме AIMD:
+// каждые increaseAfter здоровых ответов подряд лимит растёт на единицу, а блокировка
+// (в адаптивном режиме также ошибка или медленный ответ) вдвое его снижает.
+// Запросы, начатые до снижения, повторно лимит не снижают — одна волна ошибок
+// не роняет параллельность сразу до минимума.
+// Блокировка вдвое увеличивает задержку, каждые recoverAfter успешных ответов подряд
+// возвращают её на шаг обратно.
+// После threshold блокировок подряд размыкается автомат: сбор стоит cooldown,
+// затем один пробный запрос решает, продолжать или снова ждать. Запросы, начатые
+// до размыкания, на автомат уже не влияют: их ответ не закроет и не разомкнёт его
+// вместо пробного.
+type throttle struct {
+	mu   sync.Mutex
+	cond *sync.Cond
+
+	adaptive      bool
+	limit         int
+	minLimit      int
+	maxLimit      int
+	active        int
+	latency       time.Duration // медленнее — всплеск задержки, 0 — не учитывать
+	increaseAfter int
+
+	delay        time.Duration
+	baseDelay    time.Duration
+	maxDelay     time.Duration
+	recoverAfter int
+	successes    int
+
+	// Номер последнего выданного билета и билета, начиная с которого
+	// запросы снова могут снизить лимит
+	seq        uint64
+	decreaseAt uint64
+
+	state      int
+	generation uint64 // растёт при каждом размыкании автомата
+	blocks     int    // подряд
+	threshold  int
+	cooldown   time.Duration
+	openUntil  time.Time
+	pauses     int
+	maxPauses  int
+
+	report   Concurrency
+	limitSum int
+
+	// Для тестов
+	now   func() time.Time
+	sleep func(time.Duration)
+}
+
+func newThrottle(cfg ParserConfig) *throttle {
+	t := &throttle{
+		minLimit:     1,
+		maxLimit:     max(cfg.MaxGoroutines, 1),
+		delay:        cfg.RateLimit,
+		baseDelay:    cfg.RateLimit,
+		maxDelay:     time.Duration(cfg.Throttle.MaxDelayMs) * time.Millisecond,
+		recoverAfter: max(cfg.Throttle.RecoverAfter, 1),
+		threshold:    cfg.Throttle.BreakerThreshold,
+		cooldown:     time.Duration(cfg.Throttle.CooldownSeconds) * time.Second,
+		maxPauses:    cfg.Throttle.MaxPauses,
+		now:          time.Now,
+		sleep:        time.Sleep,
+	}
+	t.increaseAfter = t.recoverAfter
+
+	if cfg.Adaptive.Enabled {
+		t.adaptive = true
+		t.minLimit = max(cfg.Adaptive.MinGoroutines, 1)
+		t.maxLimit = max(cfg.Adaptive.MaxGoroutines, t.minLimit)
+		t.latency = time.Duration(cfg.Adaptive.LatencyMs) * time.Millisecond
+		t.increaseAfter = max(cfg.Adaptive.IncreaseAfter, 1)
+	}
+	t.limit = min(max(cfg.MaxGoroutines, t.minLimit), t.maxLimit)
+
+	t.report = Concurrency{
+		Adaptive: t.adaptive,
+		Min:      t.minLimit,
+		Max:      t.maxLimit,
+		Initial:  t.limit,
+		Final:    t.limit,
+		Peak:     t.limit,
+	}
+
+	t.cond = sync.NewCond(&t.mu)
+	concurrencyLimit.Set(float64(t.limit))
+	circuitOpen.Set(0)
+	return t
+}
+
+// acquire ждёт свободного слота и задержки перед запросом
+func (t *throttle) acquire() (ticket, error) {
+	t.mu.Lock()
+	for {
+		switch {
+		case t.state == breakerTripped:
+			t.mu.Unlock()
+			return ticket{}, ErrCircuitOpen
+		case t.state == breakerOpen:
+			if wait := t.openUntil.Sub(t.now()); wait > 0 {
+				t.mu.Unlock()
+				t.sleep(wait)
+				t.mu.Lock()
+				continue
+			}
+			// Пауза закончилась: этот запрос пробный
+			t.state = breakerHalfOpen
+			slog.Info("circuit breaker half-open, probing hh")
+		case t.state == breakerHalfOpen || t.active >= t.limit:
+			t.cond.Wait()
+			continue
+		}
+		break
+	}
+
+	t.active++
+	t.seq++
+	t.limitSum += t.limit
+	tk := ticket{seq: t.seq, generation: t.generation}
+	delay := t.delay
+	t.mu.Unlock()
+
+	if delay > 0 {
+		t.sleep(delay)
+	}
+	tk.start = t.now()
+	return tk, nil
+}
+
+// release учитывает итог запроса, занявшего слот в acquire
+func (t *throttle) release(tk ticket, result outcome) {
+	latency := t.now().Sub(tk.start)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	defer t.cond.Broadcast()
+
+	t.active--
+	current := tk.generation == t.generation
+
+	switch result {
+	case outcomeBlocked:
+		t.successes = 0
+		t.decrease(tk, "blocked")
+		t.slowDown()
+
+		if !current {
+			break
+		}
+		t.blocks++
+		if t.state == breakerHalfOpen || (t.threshold > 0 && t.blocks >= t.threshold) {
+			t.open()
+		}
+	case outcomeSuccess:
+		if current {
+			t.blocks = 0
+		}
+		if current && t.state == breakerHalfOpen {
+			t.state = breakerClosed
+			circuitOpen.Set(0)
+			slog.Info("circuit breaker closed, collection resumed")
+		}
+
+		if t.adaptive && t.latency > 0 && latency > t.latency {
+			t.successes = 0
+			t.decrease(tk, "latency")
+			return
+		}
+
+		t.successes++
+		if t.successes%t.increaseAfter == 0 {
+			t.increase()
+		}
+		if t.successes%t.recoverAfter == 0 {
+			t.speedUp()
+		}
+	case outcomeFailure:
+		// Пробный запрос не ответил ничего определённого — пробуем снова
+		if current && t.state == breakerHalfOpen {
+			t.state = breakerOpen
+		}
+		if t.adaptive {
+			t.successes = 0
+			t.decrease(tk, "error")
+		}
+	}
+}
+
+// decrease вдвое снижает лимит, если запрос начат после предыдущего снижения
+func (t *throttle) decrease(tk ticket, reason string) {
+	if tk.seq <= t.decreaseAt || t.limit == t.minLimit {
+		return
+	}
+	t.decreaseAt = t.seq
+	t.limit = max(t.limit/2, t.minLimit)
+	t.report.Decreases++
+	t.setLimit()
+	slog.Warn("concurrency decreased", "reason", reason, "concurrency", t.limit)
+}
+
+func (t *throttle) increase() {
+	if t.limit == t.maxLimit {
+		return
+	}
+	t.limit++
+	t.report.Increases++
+	t.setLimit()
+	slog.Debug("concurrency increased", "concurrency", t.limit)
+}
+
+func (t *throttle) setLimit() {
+	t.report.Final = t.limit
+	t.report.Peak = max(t.report.Peak, t.limit)
+	concurrencyLimit.Set(float64(t.limit))
+}
+
+func (t *throttle) slowDown() {
+	t.delay = max(t.delay*2, time.Second)
+	if t.maxDelay > 0 {
+		t.delay = min(t.delay, t.maxDelay)
+	}
+	slog.Warn("hh block, slowing down", "concurrency", t.limit, "delay", t.delay)
+}
+
+func (t *throttle) speedUp() {
+	if t.delay == t.baseDelay {
+		return
+	}
+	t.delay = max(t.delay/2, t.baseDelay)
+	slog.Info("hh responds normally, speeding up", "concurrency", t.limit, "delay", t.delay)
+}
+
+func (t *throttle) open() {
+	t.generation++
+	t.blocks = 0
+	t.pauses++
+	circuitOpen.Set(1)
+
+	if t.maxPauses > 0 && t.pauses > t.maxPauses {
+		t.state = breakerTripped
+		slog.Error("circuit breaker tripped, remaining requests are skipped", "pauses", t.pauses-1)
+		return
+	}
+
+	t.state = breakerOpen
+	t.openUntil = t.now().Add(t.cooldown)
+	slog.Warn("circuit breaker open, collection paused", "cooldown", t.cooldown, "pause", t.pauses)
+}
+
+// concurrency возвращает сводку по лимиту за сбор
+func (t *throttle) concurrency() Concurrency {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	report := t.report
+	if t.seq > 0 {
+		report.Average = math.Round(float64(t.limitSum)/float64(t.seq)*100) / 100
+	}
+	return report
+}
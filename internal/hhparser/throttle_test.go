@@ -0,0 +1,258 @@
+package hhparser
+
+import (
+	"hhparser/internal/config"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeClock — часы, которые сдвигает sleep вместо реального ожидания
+type fakeClock struct {
+	now   time.Time
+	slept time.Duration
+}
+
+func newTestThrottle(cfg ParserConfig) (*throttle, *fakeClock) {
+	clock := &fakeClock{now: time.Date(2026, 2, 15, 9, 0, 0, 0, time.UTC)}
+	t := newThrottle(cfg)
+	t.now = func() time.Time { return clock.now }
+	t.sleep = func(d time.Duration) {
+		clock.now = clock.now.Add(d)
+		clock.slept += d
+	}
+	return t, clock
+}
+
+// request проводит через throttle один запрос длительностью latency
+func request(t *testing.T, th *throttle, clock *fakeClock, result outcome, latency time.Duration) {
+	tk, err := th.acquire()
+	require.NoError(t, err)
+	clock.now = clock.now.Add(latency)
+	th.release(tk, result)
+}
+
+func TestThrottle_SlowDownAndRecover(t *testing.T) {
+	th, clock := newTestThrottle(ParserConfig{
+		MaxGoroutines: 8,
+		RateLimit:     100 * time.Millisecond,
+		Throttle:      config.ThrottleConfig{RecoverAfter: 2, MaxDelayMs: 3000},
+	})
+
+	for range 2 {
+		request(t, th, clock, outcomeBlocked, 0)
+	}
+	assert.Equal(t, 2, th.limit)
+	assert.Equal(t, 2*time.Second, th.delay, "первая блокировка поднимает задержку минимум до секунды")
+
+	request(t, th, clock, outcomeBlocked, 0)
+	assert.Equal(t, 1, th.limit)
+	assert.Equal(t, 3*time.Second, th.delay, "задержка ограничена max_delay_ms")
+
+	for range 6 {
+		request(t, th, clock, outcomeSuccess, 0)
+	}
+	assert.Equal(t, 4, th.limit)
+	assert.Equal(t, 375*time.Millisecond, th.delay)
+
+	for range 20 {
+		request(t, th, clock, outcomeSuccess, 0)
+	}
+	assert.Equal(t, 8, th.limit, "не выше max_goroutines")
+	assert.Equal(t, 100*time.Millisecond, th.delay, "не ниже rate_limit_ms")
+}
+
+func TestThrottle_FailureDoesNotSlowDown(t *testing.T) {
+	th, clock := newTestThrottle(ParserConfig{MaxGoroutines: 4})
+
+	request(t, th, clock, outcomeFailure, 0)
+
+	assert.Equal(t, 4, th.limit)
+	assert.Zero(t, th.delay)
+}
+
+func TestThrottle_CircuitBreaker(t *testing.T) {
+	th, clock := newTestThrottle(ParserConfig{MaxGoroutines: 1, Throttle: config.ThrottleConfig{
+		BreakerThreshold: 2,
+		CooldownSeconds:  60,
+		MaxPauses:        2,
+		MaxDelayMs:       1000,
+	}})
+
+	for range 2 {
+		request(t, th, clock, outcomeBlocked, 0)
+	}
+	assert.Equal(t, breakerOpen, th.state)
+
+	// Пробный запрос ждёт окончания паузы
+	before := clock.now
+	tk, err := th.acquire()
+	require.NoError(t, err)
+	assert.Equal(t, breakerHalfOpen, th.state)
+	assert.GreaterOrEqual(t, clock.now.Sub(before), time.Minute)
+	th.release(tk, outcomeSuccess)
+	assert.Equal(t, breakerClosed, th.state)
+
+	for range 2 {
+		request(t, th, clock, outcomeBlocked, 0)
+	}
+	assert.Equal(t, breakerOpen, th.state)
+
+	request(t, th, clock, outcomeBlocked, 0)
+	assert.Equal(t, breakerTripped, th.state, "третья пауза при max_pauses 2")
+
+	_, err = th.acquire()
+	assert.ErrorIs(t, err, ErrCircuitOpen)
+}
+
+func TestThrottle_HalfOpenFailureReopens(t *testing.T) {
+	th, clock := newTestThrottle(ParserConfig{MaxGoroutines: 1, Throttle: config.ThrottleConfig{BreakerThreshold: 1, CooldownSeconds: 10}})
+
+	request(t, th, clock, outcomeBlocked, 0)
+
+	request(t, th, clock, outcomeFailure, 0)
+	assert.Equal(t, breakerOpen, th.state)
+	assert.Equal(t, 1, th.pauses, "сетевая ошибка пробы не считается новой паузой")
+}
+
+func TestThrottle_HalfOpenIgnoresStaleOutcomes(t *testing.T) {
+	th, clock := newTestThrottle(ParserConfig{MaxGoroutines: 3, Throttle: config.ThrottleConfig{BreakerThreshold: 1, CooldownSeconds: 10}})
+
+	// Запросы, начатые до размыкания автомата
+	slowSuccess, err := th.acquire()
+	require.NoError(t, err)
+	slowBlocked, err := th.acquire()
+	require.NoError(t, err)
+
+	request(t, th, clock, outcomeBlocked, 0)
+	require.Equal(t, breakerOpen, th.state)
+
+	probe, err := th.acquire()
+	require.NoError(t, err)
+	require.Equal(t, breakerHalfOpen, th.state)
+
+	th.release(slowSuccess, outcomeSuccess)
+	assert.Equal(t, breakerHalfOpen, th.state, "старый успех не закрывает автомат вместо пробы")
+	th.release(slowBlocked, outcomeBlocked)
+	assert.Equal(t, breakerHalfOpen, th.state, "старая блокировка не размыкает автомат заново")
+	assert.Equal(t, 1, th.pauses)
+
+	th.release(probe, outcomeSuccess)
+	assert.Equal(t, breakerClosed, th.state)
+}
+
+func adaptiveConfig() ParserConfig {
+	return ParserConfig{
+		MaxGoroutines: 4,
+		Throttle:      config.ThrottleConfig{RecoverAfter: 20},
+		Adaptive: config.AdaptiveConfig{
+			Enabled:       true,
+			MinGoroutines: 2,
+			MaxGoroutines: 6,
+			IncreaseAfter: 2,
+			LatencyMs:     1000,
+		},
+	}
+}
+
+func TestThrottle_AdaptiveIncrease(t *testing.T) {
+	th, clock := newTestThrottle(adaptiveConfig())
+
+	for range 10 {
+		request(t, th, clock, outcomeSuccess, 100*time.Millisecond)
+	}
+
+	report := th.concurrency()
+	assert.Equal(t, 6, th.limit, "не выше adaptive.max_goroutines")
+	assert.Equal(t, 4, report.Initial)
+	assert.Equal(t, 6, report.Final)
+	assert.Equal(t, 6, report.Peak)
+	assert.Equal(t, 2, report.Increases)
+	assert.InDelta(t, 5.4, report.Average, 0.001)
+}
+
+func TestThrottle_AdaptiveDecrease(t *testing.T) {
+	th, clock := newTestThrottle(adaptiveConfig())
+
+	request(t, th, clock, outcomeSuccess, 2*time.Second)
+	assert.Equal(t, 2, th.limit, "медленный ответ снижает лимит вдвое")
+
+	request(t, th, clock, outcomeFailure, 0)
+	assert.Equal(t, 2, th.limit, "не ниже adaptive.min_goroutines")
+
+	request(t, th, clock, outcomeSuccess, 0)
+	request(t, th, clock, outcomeSuccess, 0)
+	assert.Equal(t, 3, th.limit)
+	assert.Equal(t, 1, th.concurrency().Decreases)
+}
+
+func TestThrottle_AdaptiveDecreaseOncePerWave(t *testing.T) {
+	cfg := adaptiveConfig()
+	cfg.MaxGoroutines = 6
+	cfg.Adaptive.MinGoroutines = 1
+	th, _ := newTestThrottle(cfg)
+
+	// Шесть запросов в работе одновременно, все заканчиваются ошибкой
+	var tickets []ticket
+	for range 6 {
+		tk, err := th.acquire()
+		require.NoError(t, err)
+		tickets = append(tickets, tk)
+	}
+	for _, tk := range tickets {
+		th.release(tk, outcomeFailure)
+	}
+	assert.Equal(t, 3, th.limit, "запросы одной волны снижают лимит один раз")
+
+	tk, err := th.acquire()
+	require.NoError(t, err)
+	th.release(tk, outcomeFailure)
+	assert.Equal(t, 1, th.limit, "запрос после снижения снижает снова")
+}
+
+func TestThrottle_FixedIgnoresLatency(t *testing.T) {
+	th, clock := newTestThrottle(ParserConfig{MaxGoroutines: 4})
+
+	request(t, th, clock, outcomeSuccess, time.Minute)
+	request(t, th, clock, outcomeFailure, 0)
+
+	assert.Equal(t, 4, th.limit, "без adaptive лимит снижают только блокировки")
+	assert.False(t, th.concurrency().Adaptive)
+}
+
+func TestDetectBlock(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		content string
+		want    error
+	}{
+		{"ok", 200, `{"searchCounts":{"value":10,"isLoaded":true}}`, nil},
+		{"too many requests", 429, "", ErrTooManyRequests},
+		{"captcha", 403, `<form action="/account/captcha">`, ErrCaptcha},
+		{"captcha with 200", 200, `<title>Проверка, что вы не робот</title>`, ErrCaptcha},
+		{"counts win over captcha word", 200, `captcha-input {"searchCounts":{"value":1,}}`, nil},
+		{"plain server error", 500, "Internal Server Error", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := detectBlock(tt.status, []byte(tt.content))
+			if tt.want == nil {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorIs(t, err, tt.want)
+			assert.ErrorIs(t, err, ErrBlocked)
+		})
+	}
+}
+
+func TestCacheable(t *testing.T) {
+	assert.True(t, cacheable([]byte(`{"searchCounts":{"value":10,"isLoaded":true}}`)))
+	assert.True(t, cacheable([]byte(`{"items":[],"found":0}`)), "ответ API")
+	assert.False(t, cacheable([]byte(`<title>Проверка, что вы не робот</title>`)), "капча с 200")
+	assert.False(t, cacheable([]byte(`<html>новая вёрстка</html>`)), "страница без searchCounts")
+}
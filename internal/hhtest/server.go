@@ -0,0 +1,344 @@
+// Package hhtest — поддельный hh.ru для тестов: страницы поиска и API вакансий
+// с настраиваемыми количествами и сбоями (429, 5xx, капча, изменённая вёрстка, медленные ответы).
+package hhtest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Fault — сбой, которым сервер ответит на очередной запрос вместо страницы
+type Fault string
+
+const (
+	FaultTooManyRequests Fault = "429"
+	FaultServerError     Fault = "500"
+	FaultCaptcha         Fault = "captcha" // 403 со страницей капчи
+	FaultMarkupChanged   Fault = "markup"  // 200, но без блока searchCounts
+	FaultSlow            Fault = "slow"    // обычная страница после задержки SlowDelay
+)
+
+const (
+	SearchPath = "/search/vacancy"
+	APIPath    = "/vacancies"
+
+	// SlowDelay — задержка ответа для FaultSlow, если не задана SetSlowDelay
+	SlowDelay = time.Second
+)
+
+type query struct {
+	text string
+	area int
+}
+
+// Vacancy — вакансия в выдаче API. Пустые поля в ответ не попадают.
+type Vacancy struct {
+	ID          string
+	Name        string
+	EmployerID  string
+	Employer    string
+	SalaryFrom  int
+	SalaryTo    int
+	Currency    string
+	AreaID      string
+	AreaName    string
+	Experience  string
+	Schedule    string
+	Employment  string
+	PublishedAt time.Time
+	KeySkills   []string // только на странице вакансии
+}
+
+// Server — httptest.Server, отвечающий как hh.ru
+type Server struct {
+	*httptest.Server
+
+	mu        sync.Mutex
+	counts    map[query]int
+	faults    map[query][]Fault
+	requests  map[query]int
+	total     int
+	slowDelay time.Duration
+
+	vacancies map[query][]Vacancy
+	byID      map[string]Vacancy
+	details   map[string]int
+}
+
+// New запускает сервер. Запрос без заданного количества получает 0 вакансий.
+func New() *Server {
+	s := &Server{
+		counts:    make(map[query]int),
+		faults:    make(map[query][]Fault),
+		requests:  make(map[query]int),
+		slowDelay: SlowDelay,
+		vacancies: make(map[query][]Vacancy),
+		byID:      make(map[string]Vacancy),
+		details:   make(map[string]int),
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET "+SearchPath, s.search)
+	mux.HandleFunc("GET "+APIPath, s.api)
+	mux.HandleFunc("GET "+APIPath+"/{id}", s.vacancy)
+	s.Server = httptest.NewServer(mux)
+
+	return s
+}
+
+// SearchURL — шаблон для parser.url_search_vacancies (text=%s, area=%d)
+func (s *Server) SearchURL() string {
+	return s.URL + SearchPath + "?text=%s&area=%d"
+}
+
+// SetCount задаёт количество вакансий по поисковому запросу в регионе
+func (s *Server) SetCount(text string, area, count int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.counts[query{text, area}] = count
+}
+
+// APIURL — адрес для vacancies.url
+func (s *Server) APIURL() string {
+	return s.URL + APIPath
+}
+
+// SetVacancies задаёт выдачу API по поисковому запросу в регионе.
+// Если количество не задано SetCount, found равен числу вакансий.
+func (s *Server) SetVacancies(text string, area int, vacancies ...Vacancy) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	q := query{text, area}
+	s.vacancies[q] = vacancies
+	if _, ok := s.counts[q]; !ok {
+		s.counts[q] = len(vacancies)
+	}
+	for _, v := range vacancies {
+		s.byID[v.ID] = v
+	}
+}
+
+// Fail ставит сбои в очередь: каждый следующий запрос text/area получает очередной сбой,
+// после их исчерпания сервер отвечает нормально
+func (s *Server) Fail(text string, area int, faults ...Fault) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	q := query{text, area}
+	s.faults[q] = append(s.faults[q], faults...)
+}
+
+func (s *Server) SetSlowDelay(delay time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.slowDelay = delay
+}
+
+// Requests возвращает число запросов text/area к страницам и API
+func (s *Server) Requests(text string, area int) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.requests[query{text, area}]
+}
+
+// TotalRequests возвращает число всех запросов к серверу
+func (s *Server) TotalRequests() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.total
+}
+
+// DetailRequests возвращает число запросов страницы вакансии
+func (s *Server) DetailRequests(id string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.details[id]
+}
+
+// next учитывает запрос и возвращает количество и очередной сбой
+func (s *Server) next(r *http.Request) (int, Fault) {
+	area, _ := strconv.Atoi(r.URL.Query().Get("area"))
+	q := query{r.URL.Query().Get("text"), area}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.requests[q]++
+	s.total++
+
+	var fault Fault
+	if queue := s.faults[q]; len(queue) > 0 {
+		fault, s.faults[q] = queue[0], queue[1:]
+	}
+	return s.counts[q], fault
+}
+
+// fail отвечает сбоем и сообщает, обработан ли запрос
+func (s *Server) fail(w http.ResponseWriter, r *http.Request, fault Fault) bool {
+	switch fault {
+	case FaultTooManyRequests:
+		w.Header().Set("Retry-After", "1")
+		http.Error(w, "Too Many Requests", http.StatusTooManyRequests)
+	case FaultServerError:
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+	case FaultCaptcha:
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.WriteHeader(http.StatusForbidden)
+		fmt.Fprint(w, captchaPage)
+	case FaultSlow:
+		s.mu.Lock()
+		delay := s.slowDelay
+		s.mu.Unlock()
+
+		select {
+		case <-time.After(delay):
+		case <-r.Context().Done():
+			return true
+		}
+		return false
+	default:
+		return false
+	}
+	return true
+}
+
+func (s *Server) search(w http.ResponseWriter, r *http.Request) {
+	count, fault := s.next(r)
+	if s.fail(w, r, fault) {
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if fault == FaultMarkupChanged {
+		fmt.Fprintf(w, changedSearchPage, count)
+		return
+	}
+	fmt.Fprintf(w, searchPage, count, count)
+}
+
+func (s *Server) api(w http.ResponseWriter, r *http.Request) {
+	count, fault := s.next(r)
+	if s.fail(w, r, fault) {
+		return
+	}
+
+	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+	perPage, err := strconv.Atoi(r.URL.Query().Get("per_page"))
+	if err != nil || perPage <= 0 {
+		perPage = 20
+	}
+
+	area, _ := strconv.Atoi(r.URL.Query().Get("area"))
+	s.mu.Lock()
+	vacancies := s.vacancies[query{r.URL.Query().Get("text"), area}]
+	s.mu.Unlock()
+
+	// date_from оставляет вакансии, опубликованные не раньше заданного момента
+	if dateFrom := r.URL.Query().Get("date_from"); dateFrom != "" {
+		from, err := time.Parse("2006-01-02T15:04:05-0700", dateFrom)
+		if err != nil {
+			http.Error(w, `{"errors":[{"type":"bad_argument","value":"date_from"}]}`, http.StatusBadRequest)
+			return
+		}
+		var filtered []Vacancy
+		for _, v := range vacancies {
+			if !v.PublishedAt.Before(from) {
+				filtered = append(filtered, v)
+			}
+		}
+		vacancies, count = filtered, len(filtered)
+	}
+
+	items := []any{}
+	for i := page * perPage; i < min((page+1)*perPage, len(vacancies)); i++ {
+		items = append(items, vacancies[i].json(false))
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]any{
+		"found":    count,
+		"items":    items,
+		"page":     page,
+		"pages":    (len(vacancies) + perPage - 1) / perPage,
+		"per_page": perPage,
+	})
+}
+
+func (s *Server) vacancy(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+
+	s.mu.Lock()
+	s.details[id]++
+	s.total++
+	v, ok := s.byID[id]
+	s.mu.Unlock()
+
+	if !ok {
+		http.Error(w, `{"errors":[{"type":"not_found"}]}`, http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v.json(true))
+}
+
+// json повторяет формат вакансии API hh.ru
+func (v Vacancy) json(detail bool) map[string]any {
+	item := map[string]any{
+		"id":            v.ID,
+		"name":          v.Name,
+		"alternate_url": "https://hh.ru/vacancy/" + v.ID,
+		"area":          map[string]string{"id": v.AreaID, "name": v.AreaName},
+		"published_at":  v.PublishedAt.Format("2006-01-02T15:04:05-0700"),
+		"salary":        nil,
+	}
+	if v.Employer != "" {
+		item["employer"] = map[string]string{"id": v.EmployerID, "name": v.Employer}
+	}
+	if v.SalaryFrom > 0 || v.SalaryTo > 0 {
+		salary := map[string]any{"currency": v.Currency, "gross": false, "from": nil, "to": nil}
+		if v.SalaryFrom > 0 {
+			salary["from"] = v.SalaryFrom
+		}
+		if v.SalaryTo > 0 {
+			salary["to"] = v.SalaryTo
+		}
+		item["salary"] = salary
+	}
+	for field, id := range map[string]string{"experience": v.Experience, "schedule": v.Schedule, "employment": v.Employment} {
+		if id != "" {
+			item[field] = map[string]string{"id": id}
+		}
+	}
+	if detail {
+		skills := []map[string]string{}
+		for _, skill := range v.KeySkills {
+			skills = append(skills, map[string]string{"name": skill})
+		}
+		item["key_skills"] = skills
+	}
+	return item
+}
+
+// Фрагменты вёрстки hh.ru, достаточные для парсера
+const (
+	searchPage = `<!DOCTYPE html>
+<html><head><title>Работа в России</title></head><body>
+<div class="vacancy-serp">Найдено %d вакансий</div>
+<template id="HH-Lux-InitialState">{"searchCounts":{"value":%d,"isLoaded":true},"searchClusters":{}}</template>
+</body></html>`
+
+	changedSearchPage = `<!DOCTYPE html>
+<html><body>
+<template id="HH-Lux-InitialState">{"vacancySearch":{"found":%d}}</template>
+</body></html>`
+
+	captchaPage = `<!DOCTYPE html>
+<html><head><title>Проверка, что вы не робот</title></head><body>
+<form action="/account/captcha" method="post"><img src="/captcha/picture"/></form>
+</body></html>`
+)
@@ -0,0 +1,204 @@
+package httpcache
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+)
+
+const (
+	ModeOff    = ""
+	ModeCache  = "cache"  // отдавать сохранённый ответ, пока он моложе TTL
+	ModeRecord = "record" // всегда ходить в сеть и сохранять ответы
+	ModeReplay = "replay" // работать только с сохранёнными ответами, без сети
+)
+
+var ErrNotRecorded = errors.New("httpcache: ответ не записан")
+
+// Options — режим и место хранения ответов. TTL используется только в режиме cache,
+// 0 — ответы не устаревают.
+type Options struct {
+	Mode string
+	Dir  string
+	TTL  time.Duration
+
+	// Valid проверяет тело ответа 200 перед сохранением: страница капчи или
+	// неожиданная вёрстка тоже приходят с 200, но кешировать их нельзя.
+	// Записи кеша, не прошедшие проверку, в режиме cache считаются промахом.
+	// nil — сохраняется любой ответ 200.
+	Valid func(body []byte) bool
+}
+
+// Transport — http.RoundTripper, сохраняющий успешные ответы на GET-запросы
+// в файлы, по одному на URL
+type Transport struct {
+	next    http.RoundTripper
+	options Options
+
+	// Для тестов
+	now func() time.Time
+}
+
+// entry — формат файла с сохранённым ответом
+type entry struct {
+	URL       string      `json:"url"`
+	Status    int         `json:"status"`
+	Header    http.Header `json:"header"`
+	Body      []byte      `json:"body"`
+	FetchedAt time.Time   `json:"fetchedAt"`
+}
+
+// New оборачивает next (nil — http.DefaultTransport). В режиме ModeOff возвращает next как есть.
+func New(next http.RoundTripper, options Options) (http.RoundTripper, error) {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+
+	switch options.Mode {
+	case ModeOff:
+		return next, nil
+	case ModeCache, ModeRecord, ModeReplay:
+	default:
+		return nil, fmt.Errorf("неизвестный режим кеша: %q", options.Mode)
+	}
+
+	if options.Dir == "" {
+		return nil, fmt.Errorf("для режима кеша %s нужна директория", options.Mode)
+	}
+
+	return &Transport{next: next, options: options, now: time.Now}, nil
+}
+
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method != http.MethodGet {
+		return t.next.RoundTrip(req)
+	}
+
+	url := req.URL.String()
+	path := t.path(url)
+
+	if t.options.Mode != ModeRecord {
+		cached, err := t.load(path)
+		switch {
+		case err == nil && t.fresh(cached) && t.valid(cached.Body):
+			slog.Debug("http cache hit", "url", url, "mode", t.options.Mode)
+			return cached.response(req)
+		case t.options.Mode == ModeReplay && err == nil:
+			return cached.response(req)
+		case t.options.Mode == ModeReplay:
+			return nil, fmt.Errorf("%w: %s", ErrNotRecorded, url)
+		}
+	}
+
+	res, err := t.next.RoundTrip(req)
+	if err != nil || res.StatusCode != http.StatusOK {
+		return res, err
+	}
+
+	body, err := io.ReadAll(res.Body)
+	res.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	res.Body = io.NopCloser(bytes.NewReader(body))
+
+	if !t.valid(body) {
+		slog.Debug("http cache skipped invalid response", "url", url)
+		return res, nil
+	}
+
+	if err := t.save(path, entry{URL: url, Status: res.StatusCode, Header: res.Header, Body: body, FetchedAt: t.now()}); err != nil {
+		slog.Warn("failed to save http cache entry", "url", url, "error", err)
+	}
+
+	return res, nil
+}
+
+// Path возвращает файл, в котором хранится ответ для url
+func Path(dir, url string) string {
+	sum := sha256.Sum256([]byte(url))
+	return filepath.Join(dir, hex.EncodeToString(sum[:])+".json")
+}
+
+func (t *Transport) path(url string) string {
+	return Path(t.options.Dir, url)
+}
+
+func (t *Transport) valid(body []byte) bool {
+	return t.options.Valid == nil || t.options.Valid(body)
+}
+
+func (t *Transport) fresh(cached entry) bool {
+	return t.options.TTL <= 0 || t.now().Sub(cached.FetchedAt) < t.options.TTL
+}
+
+func (t *Transport) load(path string) (entry, error) {
+	var cached entry
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return cached, err
+	}
+	if err := json.Unmarshal(data, &cached); err != nil {
+		return cached, fmt.Errorf("повреждённая запись кеша %s: %w", path, err)
+	}
+	return cached, nil
+}
+
+func (t *Transport) save(path string, cached entry) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(cached, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	// Пишем во временный файл и переименовываем, чтобы параллельные запросы
+	// не увидели половину записи
+	tmp, err := os.CreateTemp(filepath.Dir(path), "entry-*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+func (e entry) response(req *http.Request) (*http.Response, error) {
+	header := e.Header.Clone()
+	if header == nil {
+		header = make(http.Header)
+	}
+	header.Set("Content-Length", strconv.Itoa(len(e.Body)))
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", e.Status, http.StatusText(e.Status)),
+		StatusCode:    e.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(e.Body)),
+		ContentLength: int64(len(e.Body)),
+		Request:       req,
+	}, nil
+}
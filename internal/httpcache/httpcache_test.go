@@ -0,0 +1,133 @@
+package httpcache
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func origin(t *testing.T) (*httptest.Server, *atomic.Int32) {
+	var hits atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := hits.Add(1)
+		if r.URL.Path == "/missing" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprintf(w, "page %s #%d", r.URL.Query().Get("text"), n)
+	}))
+	t.Cleanup(server.Close)
+	return server, &hits
+}
+
+func client(t *testing.T, options Options) (*http.Client, *Transport) {
+	transport, err := New(nil, options)
+	require.NoError(t, err)
+	return &http.Client{Transport: transport}, transport.(*Transport)
+}
+
+func get(t *testing.T, c *http.Client, url string) (int, string) {
+	res, err := c.Get(url)
+	require.NoError(t, err)
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	require.NoError(t, err)
+	return res.StatusCode, string(body)
+}
+
+func TestTransport_CacheTTL(t *testing.T) {
+	server, hits := origin(t)
+	c, transport := client(t, Options{Mode: ModeCache, Dir: t.TempDir(), TTL: time.Hour})
+
+	now := time.Date(2026, 2, 15, 9, 0, 0, 0, time.UTC)
+	transport.now = func() time.Time { return now }
+
+	_, first := get(t, c, server.URL+"/search?text=golang")
+	_, second := get(t, c, server.URL+"/search?text=golang")
+	assert.Equal(t, "page golang #1", first)
+	assert.Equal(t, first, second, "второй ответ из кеша")
+
+	_, other := get(t, c, server.URL+"/search?text=php")
+	assert.Equal(t, "page php #2", other, "другой URL — другая запись")
+
+	now = now.Add(2 * time.Hour)
+	_, expired := get(t, c, server.URL+"/search?text=golang")
+	assert.Equal(t, "page golang #3", expired)
+	assert.EqualValues(t, 3, hits.Load())
+
+	status, _ := get(t, c, server.URL+"/missing")
+	assert.Equal(t, http.StatusNotFound, status)
+	get(t, c, server.URL+"/missing")
+	assert.EqualValues(t, 5, hits.Load(), "ошибки не кешируются")
+}
+
+func TestTransport_RecordReplay(t *testing.T) {
+	server, hits := origin(t)
+	dir := t.TempDir()
+
+	recorder, _ := client(t, Options{Mode: ModeRecord, Dir: dir})
+	get(t, recorder, server.URL+"/search?text=golang")
+	_, rerecorded := get(t, recorder, server.URL+"/search?text=golang")
+	assert.Equal(t, "page golang #2", rerecorded, "record всегда ходит в сеть")
+	assert.FileExists(t, Path(dir, server.URL+"/search?text=golang"))
+
+	server.Close()
+
+	replay, _ := client(t, Options{Mode: ModeReplay, Dir: dir, TTL: time.Nanosecond})
+	res, err := replay.Get(server.URL + "/search?text=golang")
+	require.NoError(t, err)
+	body, _ := io.ReadAll(res.Body)
+	res.Body.Close()
+
+	assert.Equal(t, "page golang #2", string(body), "replay игнорирует TTL")
+	assert.Equal(t, "text/html", res.Header.Get("Content-Type"))
+	assert.EqualValues(t, len(body), res.ContentLength)
+
+	_, err = replay.Get(server.URL + "/search?text=php")
+	assert.ErrorIs(t, err, ErrNotRecorded)
+	assert.EqualValues(t, 2, hits.Load())
+}
+
+func TestTransport_SkipsInvalid(t *testing.T) {
+	var hits atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		fmt.Fprint(w, `<form action="/account/captcha">`)
+	}))
+	t.Cleanup(server.Close)
+
+	dir := t.TempDir()
+	c, _ := client(t, Options{Mode: ModeCache, Dir: dir, TTL: time.Hour, Valid: func(body []byte) bool {
+		return !strings.Contains(string(body), "captcha")
+	}})
+
+	status, body := get(t, c, server.URL+"/search?text=golang")
+	assert.Equal(t, http.StatusOK, status)
+	assert.Contains(t, body, "captcha", "ответ отдаётся вызывающему как есть")
+	get(t, c, server.URL+"/search?text=golang")
+
+	assert.EqualValues(t, 2, hits.Load(), "капча с 200 не кешируется, повтор идёт в сеть")
+	assert.NoFileExists(t, Path(dir, server.URL+"/search?text=golang"))
+}
+
+func TestNew(t *testing.T) {
+	transport, err := New(http.DefaultTransport, Options{})
+	require.NoError(t, err)
+	assert.Same(t, http.DefaultTransport, transport)
+
+	_, err = New(nil, Options{Mode: "offline", Dir: "x"})
+	assert.Error(t, err)
+
+	_, err = New(nil, Options{Mode: ModeReplay})
+	assert.Error(t, err)
+}
@@ -0,0 +1,48 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"hhparser/internal/config"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNew_JSON(t *testing.T) {
+	var out bytes.Buffer
+	logger, err := New(&out, config.LogConfig{Level: "warn", Format: "json"})
+	require.NoError(t, err)
+
+	logger.Info("skipped")
+	logger.Warn("request failed", "technology", "Golang", "city", 1, "status", 503)
+
+	var record map[string]any
+	require.NoError(t, json.Unmarshal(out.Bytes(), &record), "в выводе должна быть одна запись")
+	assert.Equal(t, "request failed", record["msg"])
+	assert.Equal(t, "Golang", record["technology"])
+	assert.EqualValues(t, 503, record["status"])
+}
+
+func TestNew_Text(t *testing.T) {
+	var out bytes.Buffer
+	logger, err := New(&out, config.LogConfig{Level: "DEBUG"})
+	require.NoError(t, err)
+
+	logger.Debug("hh request", "attempt", 2)
+	assert.Contains(t, out.String(), "level=DEBUG msg=\"hh request\" attempt=2")
+}
+
+func TestNew_Invalid(t *testing.T) {
+	_, err := New(&bytes.Buffer{}, config.LogConfig{Level: "verbose"})
+	assert.Error(t, err)
+
+	_, err = New(&bytes.Buffer{}, config.LogConfig{Format: "xml"})
+	assert.Error(t, err)
+
+	level, err := ParseLevel("")
+	require.NoError(t, err)
+	assert.Equal(t, slog.LevelInfo, level)
+}
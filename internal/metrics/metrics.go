@@ -0,0 +1,310 @@
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Минимальная реализация текстового формата Prometheus без внешних зависимостей:
+// счётчики, gauge, гистограммы с метками и gauge, вычисляемые при каждом опросе.
+
+var DefaultBuckets = []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30}
+
+// Default — реестр, в котором регистрируются метрики пакетов
+var Default = NewRegistry()
+
+type Collector interface {
+	Name() string
+	write(w io.Writer)
+}
+
+type Registry struct {
+	mu         sync.Mutex
+	collectors map[string]Collector
+}
+
+func NewRegistry() *Registry {
+	return &Registry{collectors: make(map[string]Collector)}
+}
+
+// Register добавляет метрику; повторная регистрация с тем же именем заменяет прежнюю
+func (r *Registry) Register(c Collector) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.collectors[c.Name()] = c
+}
+
+func (r *Registry) WriteText(w io.Writer) error {
+	r.mu.Lock()
+	names := make([]string, 0, len(r.collectors))
+	for name := range r.collectors {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	collectors := make([]Collector, 0, len(names))
+	for _, name := range names {
+		collectors = append(collectors, r.collectors[name])
+	}
+	r.mu.Unlock()
+
+	buf := bufio.NewWriter(w)
+	for _, c := range collectors {
+		c.write(buf)
+	}
+	return buf.Flush()
+}
+
+func (r *Registry) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		_ = r.WriteText(w)
+	})
+}
+
+type desc struct {
+	name   string
+	help   string
+	kind   string
+	labels []string
+}
+
+func (d desc) Name() string {
+	return d.name
+}
+
+func (d desc) header(w io.Writer) {
+	fmt.Fprintf(w, "# HELP %s %s\n", d.name, d.help)
+	fmt.Fprintf(w, "# TYPE %s %s\n", d.name, d.kind)
+}
+
+// vec хранит значения по наборам меток
+type vec[T any] struct {
+	desc
+	mu     sync.Mutex
+	values map[string]*T
+	keys   map[string][]string
+	create func() *T
+}
+
+func newVec[T any](d desc, create func() *T) vec[T] {
+	values := make(map[string]*T)
+	keys := make(map[string][]string)
+	// Метрика без меток видна в /metrics с нулём ещё до первого изменения,
+	// иначе rate() и проверки на отсутствие метрики не работают
+	if len(d.labels) == 0 {
+		values[""] = create()
+		keys[""] = nil
+	}
+	return vec[T]{desc: d, values: values, keys: keys, create: create}
+}
+
+func (v *vec[T]) get(labels []string) *T {
+	if len(labels) != len(v.labels) {
+		panic(fmt.Sprintf("metrics: %s ожидает %d меток, получено %d", v.name, len(v.labels), len(labels)))
+	}
+
+	key := strings.Join(labels, "\xff")
+	value, ok := v.values[key]
+	if !ok {
+		value = v.create()
+		v.values[key] = value
+		v.keys[key] = append([]string(nil), labels...)
+	}
+	return value
+}
+
+func (v *vec[T]) sorted() []string {
+	keys := make([]string, 0, len(v.values))
+	for key := range v.values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+type CounterVec struct {
+	vec[float64]
+}
+
+func NewCounterVec(name, help string, labels ...string) *CounterVec {
+	return &CounterVec{newVec(desc{name, help, "counter", labels}, func() *float64 { return new(float64) })}
+}
+
+func (c *CounterVec) Add(value float64, labels ...string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	*c.get(labels) += value
+}
+
+func (c *CounterVec) Inc(labels ...string) {
+	c.Add(1, labels...)
+}
+
+// Value возвращает текущее значение, используется в тестах
+func (c *CounterVec) Value(labels ...string) float64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return *c.get(labels)
+}
+
+func (c *CounterVec) write(w io.Writer) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.header(w)
+	for _, key := range c.sorted() {
+		writeSample(w, c.name, c.labels, c.keys[key], "", "", *c.values[key])
+	}
+}
+
+type GaugeVec struct {
+	vec[float64]
+}
+
+func NewGaugeVec(name, help string, labels ...string) *GaugeVec {
+	return &GaugeVec{newVec(desc{name, help, "gauge", labels}, func() *float64 { return new(float64) })}
+}
+
+func (g *GaugeVec) Set(value float64, labels ...string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	*g.get(labels) = value
+}
+
+func (g *GaugeVec) Value(labels ...string) float64 {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return *g.get(labels)
+}
+
+func (g *GaugeVec) write(w io.Writer) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.header(w)
+	for _, key := range g.sorted() {
+		writeSample(w, g.name, g.labels, g.keys[key], "", "", *g.values[key])
+	}
+}
+
+type histogram struct {
+	counts []uint64
+	sum    float64
+	count  uint64
+}
+
+type HistogramVec struct {
+	vec[histogram]
+	buckets []float64
+}
+
+func NewHistogramVec(name, help string, buckets []float64, labels ...string) *HistogramVec {
+	buckets = append([]float64(nil), buckets...)
+	sort.Float64s(buckets)
+
+	return &HistogramVec{
+		vec: newVec(desc{name, help, "histogram", labels}, func() *histogram {
+			return &histogram{counts: make([]uint64, len(buckets))}
+		}),
+		buckets: buckets,
+	}
+}
+
+func (h *HistogramVec) Observe(value float64, labels ...string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	hist := h.get(labels)
+	for i, bound := range h.buckets {
+		if value <= bound {
+			hist.counts[i]++
+		}
+	}
+	hist.sum += value
+	hist.count++
+}
+
+// Count возвращает число наблюдений, используется в тестах
+func (h *HistogramVec) Count(labels ...string) uint64 {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.get(labels).count
+}
+
+func (h *HistogramVec) write(w io.Writer) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.header(w)
+	for _, key := range h.sorted() {
+		hist, labels := h.values[key], h.keys[key]
+		for i, bound := range h.buckets {
+			writeSample(w, h.name+"_bucket", h.labels, labels, "le", formatFloat(bound), float64(hist.counts[i]))
+		}
+		writeSample(w, h.name+"_bucket", h.labels, labels, "le", "+Inf", float64(hist.count))
+		writeSample(w, h.name+"_sum", h.labels, labels, "", "", hist.sum)
+		writeSample(w, h.name+"_count", h.labels, labels, "", "", float64(hist.count))
+	}
+}
+
+// Sample — значение вычисляемой метрики
+type Sample struct {
+	Labels []string
+	Value  float64
+}
+
+// GaugeFunc вычисляет значения при каждом опросе /metrics
+type GaugeFunc struct {
+	desc
+	collect func() []Sample
+}
+
+func NewGaugeFunc(name, help string, labels []string, collect func() []Sample) *GaugeFunc {
+	return &GaugeFunc{desc: desc{name, help, "gauge", labels}, collect: collect}
+}
+
+func (g *GaugeFunc) write(w io.Writer) {
+	g.header(w)
+	for _, sample := range g.collect() {
+		writeSample(w, g.name, g.labels, sample.Labels, "", "", sample.Value)
+	}
+}
+
+func writeSample(w io.Writer, name string, names, values []string, extraName, extraValue string, value float64) {
+	var pairs []string
+	for i, label := range names {
+		pairs = append(pairs, label+`="`+escape(values[i])+`"`)
+	}
+	if extraName != "" {
+		pairs = append(pairs, extraName+`="`+extraValue+`"`)
+	}
+
+	if len(pairs) == 0 {
+		fmt.Fprintf(w, "%s %s\n", name, formatFloat(value))
+		return
+	}
+	fmt.Fprintf(w, "%s{%s} %s\n", name, strings.Join(pairs, ","), formatFloat(value))
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func escape(value string) string {
+	return labelEscaper.Replace(value)
+}
+
+func formatFloat(value float64) string {
+	switch {
+	case math.IsInf(value, 1):
+		return "+Inf"
+	case math.IsInf(value, -1):
+		return "-Inf"
+	}
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
@@ -0,0 +1,92 @@
+package metrics
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistry_WriteText(t *testing.T) {
+	registry := NewRegistry()
+
+	requests := NewCounterVec("test_requests_total", "Запросы", "status")
+	requests.Inc("200")
+	requests.Inc("200")
+	requests.Add(3, "429")
+
+	duration := NewGaugeVec("test_run_duration_seconds", "Длительность")
+	duration.Set(12.5)
+
+	latency := NewHistogramVec("test_latency_seconds", "Задержка", []float64{1, 0.1})
+	latency.Observe(0.05)
+	latency.Observe(0.5)
+	latency.Observe(3)
+
+	vacancies := NewGaugeFunc("test_vacancies", "Вакансии", []string{"technology", "city"}, func() []Sample {
+		return []Sample{{Labels: []string{`C"++`, "MOSCOW"}, Value: 783}}
+	})
+
+	registry.Register(requests)
+	registry.Register(duration)
+	registry.Register(latency)
+	registry.Register(vacancies)
+
+	var out strings.Builder
+	require.NoError(t, registry.WriteText(&out))
+
+	expected := `# HELP test_latency_seconds Задержка
+# TYPE test_latency_seconds histogram
+test_latency_seconds_bucket{le="0.1"} 1
+test_latency_seconds_bucket{le="1"} 2
+test_latency_seconds_bucket{le="+Inf"} 3
+test_latency_seconds_sum 3.55
+test_latency_seconds_count 3
+# HELP test_requests_total Запросы
+# TYPE test_requests_total counter
+test_requests_total{status="200"} 2
+test_requests_total{status="429"} 3
+# HELP test_run_duration_seconds Длительность
+# TYPE test_run_duration_seconds gauge
+test_run_duration_seconds 12.5
+# HELP test_vacancies Вакансии
+# TYPE test_vacancies gauge
+test_vacancies{technology="C\"++",city="MOSCOW"} 783
+`
+	assert.Equal(t, expected, out.String())
+}
+
+func TestRegistry_Handler(t *testing.T) {
+	registry := NewRegistry()
+	counter := NewCounterVec("test_total", "Тест")
+	counter.Inc()
+	registry.Register(counter)
+
+	rec := httptest.NewRecorder()
+	registry.Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+
+	assert.Equal(t, 200, rec.Code)
+	assert.Contains(t, rec.Header().Get("Content-Type"), "text/plain")
+	assert.Contains(t, rec.Body.String(), "test_total 1")
+}
+
+func TestRegistry_UnlabeledStartAtZero(t *testing.T) {
+	registry := NewRegistry()
+	registry.Register(NewCounterVec("test_retries_total", "Повторы"))
+	registry.Register(NewGaugeVec("test_circuit_open", "Пауза"))
+	registry.Register(NewCounterVec("test_requests_total", "Запросы", "status"))
+
+	var out strings.Builder
+	require.NoError(t, registry.WriteText(&out))
+
+	assert.Contains(t, out.String(), "\ntest_circuit_open 0\n")
+	assert.Contains(t, out.String(), "\ntest_retries_total 0\n")
+	assert.NotContains(t, out.String(), "test_requests_total{", "значения с метками появляются при первом изменении")
+}
+
+func TestCounterVec_WrongLabelCount(t *testing.T) {
+	counter := NewCounterVec("test_total", "Тест", "status")
+	assert.Panics(t, func() { counter.Inc() })
+}
@@ -0,0 +1,208 @@
+package notify
+
+import (
+	"fmt"
+	"hhparser/internal/storage"
+	"math"
+	"sort"
+	"time"
+)
+
+const (
+	PeriodDaily  = "daily"
+	PeriodWeekly = "weekly"
+
+	// Для поиска максимумов и аномалий нужна хотя бы такая история
+	minHistory = 3
+)
+
+// Digest — сводка за период по сохранённым запускам
+type Digest struct {
+	Period    string
+	From      string
+	To        string
+	Total     storage.Change
+	TopMovers []storage.TechnologyDiff
+	NewHighs  []Extreme
+	NewLows   []Extreme
+	Anomalies []Anomaly
+
+	// Последний запуск, из него строятся вложения
+	Latest storage.Statistics
+}
+
+// Extreme — технология, достигшая максимума или минимума за всю историю
+type Extreme struct {
+	Technology string
+	Count      int
+	Previous   int // прежний рекорд
+}
+
+// Anomaly — значение, отклоняющееся от среднего по истории больше чем на заданное число σ
+type Anomaly struct {
+	Technology string
+	Count      int
+	Mean       float64
+	ZScore     float64
+}
+
+// BuildDigest строит сводку по истории запусков (по возрастанию даты). Период сравнения:
+// daily — последний запуск против предыдущего дня, weekly — против запуска недельной давности.
+func BuildDigest(history []storage.Statistics, period string, topMovers int, anomalyZ float64) (Digest, error) {
+	if len(history) == 0 {
+		return Digest{}, storage.ErrRunNotFound
+	}
+
+	latest := history[len(history)-1]
+
+	var span time.Duration
+	switch period {
+	case PeriodDaily:
+		span = 24 * time.Hour
+	case PeriodWeekly:
+		span = 7 * 24 * time.Hour
+	default:
+		return Digest{}, fmt.Errorf("неизвестный период дайджеста: %q", period)
+	}
+
+	base := baseline(history, latest.Date.Add(-span))
+	diff := storage.Compare(base, latest)
+	diff.Technologies = withoutFailed(diff.Technologies, base, latest)
+
+	digest := Digest{
+		Period:    period,
+		From:      diff.From,
+		To:        diff.To,
+		Total:     diff.Total,
+		TopMovers: movers(diff, topMovers),
+		Latest:    latest,
+	}
+
+	if len(history) >= minHistory {
+		digest.NewHighs, digest.NewLows = extremes(history)
+		digest.Anomalies = anomalies(history, anomalyZ)
+	}
+
+	return digest, nil
+}
+
+// baseline возвращает последний запуск не позже since или самый ранний из имеющихся
+func baseline(history []storage.Statistics, since time.Time) storage.Statistics {
+	day := since.Format("2006-01-02")
+	for i := len(history) - 2; i >= 0; i-- {
+		if history[i].Date.Format("2006-01-02") <= day {
+			return history[i]
+		}
+	}
+	return history[0]
+}
+
+func movers(diff storage.Diff, limit int) []storage.TechnologyDiff {
+	var result []storage.TechnologyDiff
+	for _, tech := range diff.Technologies {
+		if tech.Delta != 0 {
+			result = append(result, tech)
+		}
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return abs(result[i].Delta) > abs(result[j].Delta)
+	})
+
+	if limit > 0 && len(result) > limit {
+		result = result[:limit]
+	}
+	return result
+}
+
+func extremes(history []storage.Statistics) (highs, lows []Extreme) {
+	latest := history[len(history)-1]
+
+	for _, key := range sortedKeys(latest.Summary) {
+		count := latest.Summary[key]
+		high, low, seen := math.MinInt, math.MaxInt, false
+
+		for _, stats := range history[:len(history)-1] {
+			value, ok := stats.Summary[key]
+			if !ok {
+				continue
+			}
+			seen = true
+			high = max(high, value)
+			low = min(low, value)
+		}
+
+		if !seen {
+			continue
+		}
+		if count > high {
+			highs = append(highs, Extreme{Technology: key, Count: count, Previous: high})
+		}
+		if count < low {
+			lows = append(lows, Extreme{Technology: key, Count: count, Previous: low})
+		}
+	}
+
+	return highs, lows
+}
+
+func anomalies(history []storage.Statistics, threshold float64) []Anomaly {
+	if threshold <= 0 {
+		return nil
+	}
+
+	latest := history[len(history)-1]
+
+	var result []Anomaly
+	for _, key := range sortedKeys(latest.Summary) {
+		var values []float64
+		for _, stats := range history[:len(history)-1] {
+			if value, ok := stats.Summary[key]; ok {
+				values = append(values, float64(value))
+			}
+		}
+		if len(values) < minHistory-1 {
+			continue
+		}
+
+		mean, stddev := meanStddev(values)
+		if stddev == 0 {
+			continue
+		}
+
+		z := (float64(latest.Summary[key]) - mean) / stddev
+		if math.Abs(z) >= threshold {
+			result = append(result, Anomaly{
+				Technology: key,
+				Count:      latest.Summary[key],
+				Mean:       math.Round(mean*10) / 10,
+				ZScore:     math.Round(z*100) / 100,
+			})
+		}
+	}
+
+	return result
+}
+
+func meanStddev(values []float64) (float64, float64) {
+	var sum float64
+	for _, v := range values {
+		sum += v
+	}
+	mean := sum / float64(len(values))
+
+	var variance float64
+	for _, v := range values {
+		variance += (v - mean) * (v - mean)
+	}
+	return mean, math.Sqrt(variance / float64(len(values)))
+}
+
+func sortedKeys(m map[string]int) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,197 @@
+package notify
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"hhparser/internal/config"
+	"hhparser/internal/storage"
+	htmltemplate "html/template"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/smtp"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+)
+
+const digestText = `Дайджест вакансий ({{.Period}}): {{.From}} — {{.To}}
+Всего вакансий: {{.Total.To}} ({{printf "%+d" .Total.Delta}}, {{printf "%+.1f" .Total.Percent}}%)
+{{if .TopMovers}}
+Самые заметные изменения:
+{{range .TopMovers}}  {{.Technology}}: {{.From}} → {{.To}} ({{printf "%+d" .Delta}}, {{printf "%+.1f" .Percent}}%)
+{{end}}{{end}}{{if .NewHighs}}
+Новые максимумы:
+{{range .NewHighs}}  {{.Technology}}: {{.Count}} (прежний {{.Previous}})
+{{end}}{{end}}{{if .NewLows}}
+Новые минимумы:
+{{range .NewLows}}  {{.Technology}}: {{.Count}} (прежний {{.Previous}})
+{{end}}{{end}}{{if .Anomalies}}
+Аномалии:
+{{range .Anomalies}}  {{.Technology}}: {{.Count}} при среднем {{.Mean}} (z = {{.ZScore}})
+{{end}}{{end}}`
+
+const digestHTML = `<!DOCTYPE html>
+<html><body style="font-family: sans-serif">
+<h2>Дайджест вакансий ({{.Period}}): {{.From}} — {{.To}}</h2>
+<p>Всего вакансий: <b>{{.Total.To}}</b> ({{printf "%+d" .Total.Delta}}, {{printf "%+.1f" .Total.Percent}}%)</p>
+{{if .TopMovers}}<h3>Самые заметные изменения</h3>
+<table border="1" cellpadding="4" cellspacing="0">
+<tr><th>Технология</th><th>Было</th><th>Стало</th><th>Δ</th><th>%</th></tr>
+{{range .TopMovers}}<tr><td>{{.Technology}}</td><td>{{.From}}</td><td>{{.To}}</td><td>{{printf "%+d" .Delta}}</td><td>{{printf "%+.1f" .Percent}}</td></tr>
+{{end}}</table>{{end}}
+{{if .NewHighs}}<h3>Новые максимумы</h3><ul>
+{{range .NewHighs}}<li>{{.Technology}}: {{.Count}} (прежний {{.Previous}})</li>
+{{end}}</ul>{{end}}
+{{if .NewLows}}<h3>Новые минимумы</h3><ul>
+{{range .NewLows}}<li>{{.Technology}}: {{.Count}} (прежний {{.Previous}})</li>
+{{end}}</ul>{{end}}
+{{if .Anomalies}}<h3>Аномалии</h3><ul>
+{{range .Anomalies}}<li>{{.Technology}}: {{.Count}} при среднем {{.Mean}} (z = {{.ZScore}})</li>
+{{end}}</ul>{{end}}
+</body></html>`
+
+var (
+	digestTextTemplate = template.Must(template.New("digest").Parse(digestText))
+	digestHTMLTemplate = htmltemplate.Must(htmltemplate.New("digest").Parse(digestHTML))
+)
+
+// Attachment — файл во вложении письма
+type Attachment struct {
+	Name        string
+	ContentType string
+	Data        []byte
+}
+
+// SendDigest отправляет дайджест письмом: HTML с текстовой альтернативой
+// и вложениями TXT/CSV последнего запуска
+func SendDigest(cfg config.EmailConfig, digest Digest) error {
+	message, err := DigestMessage(cfg, digest, time.Now())
+	if err != nil {
+		return err
+	}
+
+	addr := net.JoinHostPort(cfg.Host, strconv.Itoa(cfg.Port))
+
+	var auth smtp.Auth
+	if cfg.Username != "" {
+		auth = smtp.PlainAuth("", cfg.Username, cfg.Password, cfg.Host)
+	}
+
+	if err := smtp.SendMail(addr, auth, cfg.From, cfg.To, message); err != nil {
+		return fmt.Errorf("failed to send digest: %w", err)
+	}
+	return nil
+}
+
+// DigestMessage собирает MIME-письмо с дайджестом
+func DigestMessage(cfg config.EmailConfig, digest Digest, now time.Time) ([]byte, error) {
+	var text, html bytes.Buffer
+	if err := digestTextTemplate.Execute(&text, digest); err != nil {
+		return nil, fmt.Errorf("failed to render digest: %w", err)
+	}
+	if err := digestHTMLTemplate.Execute(&html, digest); err != nil {
+		return nil, fmt.Errorf("failed to render digest: %w", err)
+	}
+
+	attachments, err := digestAttachments(digest.Latest)
+	if err != nil {
+		return nil, err
+	}
+
+	subject := fmt.Sprintf("%sДайджест вакансий %s — %s", cfg.SubjectPrefix, digest.From, digest.To)
+	return buildMessage(cfg.From, cfg.To, subject, now, text.Bytes(), html.Bytes(), attachments)
+}
+
+func digestAttachments(stats storage.Statistics) ([]Attachment, error) {
+	date := stats.Date.Format("2006-01-02")
+
+	var txt, csv bytes.Buffer
+	if err := storage.WriteTXT(&txt, stats); err != nil {
+		return nil, err
+	}
+	if err := storage.WriteCSV(&csv, stats); err != nil {
+		return nil, err
+	}
+
+	return []Attachment{
+		{Name: "stats_" + date + ".txt", ContentType: "text/plain; charset=utf-8", Data: txt.Bytes()},
+		{Name: "stats_" + date + ".csv", ContentType: "text/csv; charset=utf-8", Data: csv.Bytes()},
+	}, nil
+}
+
+// buildMessage: multipart/mixed { multipart/alternative { text, html }, вложения... }
+func buildMessage(from string, to []string, subject string, date time.Time, text, html []byte, attachments []Attachment) ([]byte, error) {
+	var body bytes.Buffer
+	mixed := multipart.NewWriter(&body)
+
+	var alternative bytes.Buffer
+	alt := multipart.NewWriter(&alternative)
+	for _, part := range []struct {
+		contentType string
+		data        []byte
+	}{
+		{"text/plain; charset=utf-8", text},
+		{"text/html; charset=utf-8", html},
+	} {
+		w, err := alt.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {part.contentType},
+			"Content-Transfer-Encoding": {"base64"},
+		})
+		if err != nil {
+			return nil, err
+		}
+		writeBase64(w, part.data)
+	}
+	if err := alt.Close(); err != nil {
+		return nil, err
+	}
+
+	w, err := mixed.CreatePart(textproto.MIMEHeader{
+		"Content-Type": {"multipart/alternative; boundary=" + alt.Boundary()},
+	})
+	if err != nil {
+		return nil, err
+	}
+	w.Write(alternative.Bytes())
+
+	for _, attachment := range attachments {
+		w, err := mixed.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {attachment.ContentType},
+			"Content-Transfer-Encoding": {"base64"},
+			"Content-Disposition":       {mime.FormatMediaType("attachment", map[string]string{"filename": attachment.Name})},
+		})
+		if err != nil {
+			return nil, err
+		}
+		writeBase64(w, attachment.Data)
+	}
+	if err := mixed.Close(); err != nil {
+		return nil, err
+	}
+
+	var message bytes.Buffer
+	fmt.Fprintf(&message, "From: %s\r\n", from)
+	fmt.Fprintf(&message, "To: %s\r\n", strings.Join(to, ", "))
+	fmt.Fprintf(&message, "Subject: %s\r\n", mime.BEncoding.Encode("utf-8", subject))
+	fmt.Fprintf(&message, "Date: %s\r\n", date.Format(time.RFC1123Z))
+	fmt.Fprintf(&message, "MIME-Version: 1.0\r\n")
+	fmt.Fprintf(&message, "Content-Type: multipart/mixed; boundary=%s\r\n\r\n", mixed.Boundary())
+	message.Write(body.Bytes())
+
+	return message.Bytes(), nil
+}
+
+// writeBase64 пишет данные в base64 строками по 76 символов (RFC 2045)
+func writeBase64(w io.Writer, data []byte) {
+	encoded := base64.StdEncoding.EncodeToString(data)
+	for len(encoded) > 76 {
+		w.Write([]byte(encoded[:76] + "\r\n"))
+		encoded = encoded[76:]
+	}
+	w.Write([]byte(encoded + "\r\n"))
+}
@@ -0,0 +1,238 @@
+package notify
+
+import (
+	"bufio"
+	"encoding/base64"
+	"hhparser/internal/config"
+	"hhparser/internal/storage"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/mail"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// smtpStub — минимальный SMTP-сервер, принимающий одно письмо
+type smtpStub struct {
+	listener   net.Listener
+	from       string
+	recipients []string
+	data       chan string
+}
+
+func newSMTPStub(t *testing.T) *smtpStub {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	stub := &smtpStub{listener: listener, data: make(chan string, 1)}
+	t.Cleanup(func() { listener.Close() })
+
+	go stub.serve()
+	return stub
+}
+
+func (s *smtpStub) port() int {
+	return s.listener.Addr().(*net.TCPAddr).Port
+}
+
+func (s *smtpStub) serve() {
+	conn, err := s.listener.Accept()
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	r := bufio.NewReader(conn)
+	reply := func(line string) { io.WriteString(conn, line+"\r\n") }
+
+	reply("220 localhost ESMTP stub")
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		command := strings.ToUpper(strings.TrimSpace(line))
+
+		switch {
+		case strings.HasPrefix(command, "EHLO"), strings.HasPrefix(command, "HELO"):
+			reply("250 localhost")
+		case strings.HasPrefix(command, "MAIL FROM:"):
+			s.from = strings.Trim(strings.TrimSpace(line)[10:], "<>")
+			reply("250 OK")
+		case strings.HasPrefix(command, "RCPT TO:"):
+			s.recipients = append(s.recipients, strings.Trim(strings.TrimSpace(line)[8:], "<>"))
+			reply("250 OK")
+		case command == "DATA":
+			reply("354 End data with <CR><LF>.<CR><LF>")
+			var data strings.Builder
+			for {
+				line, err := r.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if line == ".\r\n" {
+					break
+				}
+				data.WriteString(strings.TrimPrefix(line, "."))
+			}
+			s.data <- data.String()
+			reply("250 OK")
+		case command == "QUIT":
+			reply("221 Bye")
+			return
+		default:
+			reply("250 OK")
+		}
+	}
+}
+
+func digestHistory() []storage.Statistics {
+	var history []storage.Statistics
+	for day, golang := range []int{400, 405, 398, 402, 401, 399, 403, 520} {
+		history = append(history, storage.Statistics{
+			Date:         time.Date(2026, 2, 8+day, 9, 0, 0, 0, time.UTC),
+			Technologies: []config.TechnologyConfig{{Name: "Golang", Category: "languages"}, {Name: "Php"}},
+			Cities: []storage.CityStatistics{
+				{Name: "MOSCOW", Vacancies: map[string]int{"Golang": golang, "Php": 300 - day*10}, Total: golang + 300 - day*10},
+			},
+			Summary: map[string]int{"Golang": golang, "Php": 300 - day*10},
+			Total:   golang + 300 - day*10,
+		})
+	}
+	return history
+}
+
+func TestBuildDigest(t *testing.T) {
+	digest, err := BuildDigest(digestHistory(), PeriodWeekly, 5, 3)
+	require.NoError(t, err)
+
+	assert.Equal(t, "2026-02-08", digest.From)
+	assert.Equal(t, "2026-02-15", digest.To)
+
+	require.Len(t, digest.TopMovers, 2)
+	assert.Equal(t, "Golang", digest.TopMovers[0].Technology)
+	assert.Equal(t, 120, digest.TopMovers[0].Delta)
+
+	require.Len(t, digest.NewHighs, 1)
+	assert.Equal(t, Extreme{Technology: "Golang", Count: 520, Previous: 405}, digest.NewHighs[0])
+	require.Len(t, digest.NewLows, 1)
+	assert.Equal(t, "Php", digest.NewLows[0].Technology)
+
+	require.Len(t, digest.Anomalies, 1, "равномерное снижение Php не аномалия при z=3")
+	assert.Equal(t, "Golang", digest.Anomalies[0].Technology)
+
+	daily, err := BuildDigest(digestHistory(), PeriodDaily, 1, 0)
+	require.NoError(t, err)
+	assert.Equal(t, "2026-02-14", daily.From)
+	assert.Len(t, daily.TopMovers, 1)
+	assert.Empty(t, daily.Anomalies)
+
+	_, err = BuildDigest(nil, PeriodDaily, 5, 3)
+	assert.ErrorIs(t, err, storage.ErrRunNotFound)
+
+	_, err = BuildDigest(digestHistory(), "monthly", 5, 3)
+	assert.Error(t, err)
+}
+
+func TestSendDigest_SMTPStub(t *testing.T) {
+	stub := newSMTPStub(t)
+
+	digest, err := BuildDigest(digestHistory(), PeriodDaily, 5, 3)
+	require.NoError(t, err)
+
+	cfg := config.EmailConfig{
+		Host:          "127.0.0.1",
+		Port:          stub.port(),
+		From:          "parser@example.com",
+		To:            []string{"team@example.com", "lead@example.com"},
+		SubjectPrefix: "[hh] ",
+	}
+	require.NoError(t, SendDigest(cfg, digest))
+
+	var raw string
+	select {
+	case raw = <-stub.data:
+	case <-time.After(5 * time.Second):
+		t.Fatal("письмо не получено")
+	}
+
+	assert.Equal(t, "parser@example.com", stub.from)
+	assert.Equal(t, cfg.To, stub.recipients)
+
+	msg, err := mail.ReadMessage(strings.NewReader(raw))
+	require.NoError(t, err)
+
+	subject, err := new(mime.WordDecoder).DecodeHeader(msg.Header.Get("Subject"))
+	require.NoError(t, err)
+	assert.Equal(t, "[hh] Дайджест вакансий 2026-02-14 — 2026-02-15", subject)
+
+	mediaType, params, err := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	require.NoError(t, err)
+	assert.Equal(t, "multipart/mixed", mediaType)
+
+	parts := readParts(t, msg.Body, params["boundary"])
+	require.Len(t, parts, 3)
+
+	assert.Contains(t, parts[0].contentType, "multipart/alternative")
+	alternatives := readParts(t, strings.NewReader(parts[0].body), parts[0].boundary)
+	require.Len(t, alternatives, 2)
+	assert.Contains(t, alternatives[0].body, "Golang: 403 → 520")
+	assert.Contains(t, alternatives[1].body, "<td>Golang</td>")
+
+	assert.Equal(t, "stats_2026-02-15.txt", parts[1].filename)
+	assert.Contains(t, parts[1].body, "СТАТИСТИКА ВАКАНСИЙ")
+	assert.Equal(t, "stats_2026-02-15.csv", parts[2].filename)
+	assert.Contains(t, parts[2].body, "Golang,languages,520,520")
+}
+
+type part struct {
+	contentType string
+	boundary    string
+	filename    string
+	body        string
+}
+
+func readParts(t *testing.T, body io.Reader, boundary string) []part {
+	var parts []part
+
+	reader := multipart.NewReader(body, boundary)
+	for {
+		p, err := reader.NextPart()
+		if err == io.EOF {
+			return parts
+		}
+		require.NoError(t, err)
+
+		data, err := io.ReadAll(p)
+		require.NoError(t, err)
+
+		if p.Header.Get("Content-Transfer-Encoding") == "base64" {
+			data, err = base64.StdEncoding.DecodeString(strings.ReplaceAll(string(data), "\r\n", ""))
+			require.NoError(t, err)
+		}
+
+		_, params, _ := mime.ParseMediaType(p.Header.Get("Content-Type"))
+		parts = append(parts, part{
+			contentType: p.Header.Get("Content-Type"),
+			boundary:    params["boundary"],
+			filename:    p.FileName(),
+			body:        string(data),
+		})
+	}
+}
+
+func TestWriteBase64_LineLength(t *testing.T) {
+	var out strings.Builder
+	writeBase64(&out, []byte(strings.Repeat("вакансии", 40)))
+
+	for _, line := range strings.Split(strings.TrimSuffix(out.String(), "\r\n"), "\r\n") {
+		assert.LessOrEqual(t, len(line), 76, strconv.Quote(line))
+	}
+}
@@ -0,0 +1,266 @@
+package notify
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hhparser/internal/config"
+	"hhparser/internal/hhparser"
+	"hhparser/internal/storage"
+	"io"
+	"math"
+	"net/http"
+	"strings"
+	"text/template"
+	"time"
+)
+
+const (
+	EventSuccess = "success"
+	EventFailure = "failure"
+	EventChange  = "change"
+
+	FormatJSON     = "json"
+	FormatSlack    = "slack"
+	FormatTelegram = "telegram"
+
+	// SignatureHeader содержит HMAC-SHA256 тела запроса в виде "sha256=<hex>"
+	SignatureHeader = "X-Hhparser-Signature"
+
+	webhookTimeout = 10 * time.Second
+)
+
+var defaultTemplates = map[string]string{
+	EventSuccess: `Сбор вакансий за {{.Date}} завершён: {{.Total}} вакансий{{if .Failures}}, запросов с ошибкой: {{.Failures}}{{end}}`,
+	EventFailure: `Сбор вакансий за {{.Date}} завершился ошибкой: {{.Error}}`,
+	EventChange: `Заметные изменения за {{.Date}} (с {{.PreviousDate}}):
+{{range .Changes}}{{.Technology}}: {{.From}} → {{.To}} ({{printf "%+d" .Delta}}, {{printf "%+.1f" .Percent}}%)
+{{end}}`,
+}
+
+// Event — данные уведомления, они же доступны в шаблонах сообщений
+type Event struct {
+	Type         string                   `json:"event"`
+	Date         string                   `json:"date"`
+	PreviousDate string                   `json:"previousDate,omitempty"`
+	Total        int                      `json:"total,omitempty"`
+	Failures     int                      `json:"failures,omitempty"` // запросов с ошибкой: числа неполные
+	Error        string                   `json:"error,omitempty"`
+	Changes      []storage.TechnologyDiff `json:"changes,omitempty"`
+	Message      string                   `json:"message"`
+}
+
+type Notifier struct {
+	cfg    config.NotifyConfig
+	client *http.Client
+
+	// Для тестов
+	sleep func(time.Duration)
+}
+
+func New(cfg config.NotifyConfig) *Notifier {
+	return &Notifier{
+		cfg:    cfg,
+		client: &http.Client{Timeout: webhookTimeout},
+		sleep:  time.Sleep,
+	}
+}
+
+// RunSucceeded отправляет уведомление об успешном запуске и, если есть предыдущий
+// запуск, об изменениях технологий сверх порога
+func (n *Notifier) RunSucceeded(stats storage.Statistics, previous *storage.Statistics) error {
+	date := stats.Date.Format("2006-01-02")
+
+	success := Event{Type: EventSuccess, Date: date, Total: stats.Total}
+	if stats.Manifest != nil {
+		success.Failures = stats.Manifest.Failures
+	}
+	errs := []error{n.send(success)}
+
+	if previous != nil {
+		diff := storage.Compare(*previous, stats)
+		diff.Technologies = withoutFailed(diff.Technologies, *previous, stats)
+		changes := SignificantChanges(diff, n.cfg.ChangeThresholdPercent, n.cfg.ChangeThresholdAbsolute)
+		if len(changes) > 0 {
+			errs = append(errs, n.send(Event{
+				Type:         EventChange,
+				Date:         date,
+				PreviousDate: previous.Date.Format("2006-01-02"),
+				Total:        stats.Total,
+				Changes:      changes,
+			}))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// withoutFailed убирает технологии, у которых в одном из запусков был запрос с ошибкой:
+// его ноль — не данные, и сравнение дало бы ложное падение или рост с нуля
+func withoutFailed(technologies []storage.TechnologyDiff, runs ...storage.Statistics) []storage.TechnologyDiff {
+	failed := make(map[string]bool)
+	for _, run := range runs {
+		if run.Manifest == nil {
+			continue
+		}
+		for _, query := range run.Manifest.Queries {
+			if query.Status == hhparser.QueryFailed {
+				failed[query.Technology] = true
+			}
+		}
+	}
+
+	var result []storage.TechnologyDiff
+	for _, tech := range technologies {
+		if !failed[tech.Technology] {
+			result = append(result, tech)
+		}
+	}
+	return result
+}
+
+func (n *Notifier) RunFailed(date time.Time, runErr error) error {
+	return n.send(Event{Type: EventFailure, Date: date.Format("2006-01-02"), Error: runErr.Error()})
+}
+
+// SignificantChanges отбирает технологии, изменившиеся не меньше чем на percent процентов
+// и не меньше чем на absolute вакансий. Появление технологии с нуля считается значимым.
+func SignificantChanges(diff storage.Diff, percent float64, absolute int) []storage.TechnologyDiff {
+	var changes []storage.TechnologyDiff
+	for _, tech := range diff.Technologies {
+		if tech.Delta == 0 || abs(tech.Delta) < absolute {
+			continue
+		}
+		if tech.From != 0 && math.Abs(tech.Percent) < percent {
+			continue
+		}
+		changes = append(changes, tech)
+	}
+	return changes
+}
+
+func (n *Notifier) send(event Event) error {
+	var errs []error
+	for _, hook := range n.cfg.Webhooks {
+		if !subscribed(hook, event.Type) {
+			continue
+		}
+		if err := n.deliver(hook, event); err != nil {
+			errs = append(errs, fmt.Errorf("webhook %s: %w", hook.Name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func subscribed(hook config.WebhookConfig, eventType string) bool {
+	if len(hook.Events) == 0 {
+		return true
+	}
+	for _, e := range hook.Events {
+		if e == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+func (n *Notifier) deliver(hook config.WebhookConfig, event Event) error {
+	message, err := render(hook, event)
+	if err != nil {
+		return err
+	}
+	event.Message = message
+
+	body, err := payload(hook, event)
+	if err != nil {
+		return err
+	}
+
+	delay := time.Duration(n.cfg.RetryDelayMs) * time.Millisecond
+	for attempt := 0; ; attempt++ {
+		retry, err := n.post(hook, body)
+		if err == nil {
+			return nil
+		}
+		if !retry || attempt >= hook.Retries {
+			return err
+		}
+
+		n.sleep(delay)
+		delay *= 2
+	}
+}
+
+// post возвращает признак того, что ошибку имеет смысл повторить
+func (n *Notifier) post(hook config.WebhookConfig, body []byte) (bool, error) {
+	req, err := http.NewRequest(http.MethodPost, hook.Url, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if hook.Secret != "" {
+		req.Header.Set(SignatureHeader, Sign(body, hook.Secret))
+	}
+
+	res, err := n.client.Do(req)
+	if err != nil {
+		return true, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode >= 200 && res.StatusCode < 300 {
+		return false, nil
+	}
+
+	message, _ := io.ReadAll(io.LimitReader(res.Body, 512))
+	err = fmt.Errorf("status %d: %s", res.StatusCode, strings.TrimSpace(string(message)))
+	return res.StatusCode == http.StatusTooManyRequests || res.StatusCode >= 500, err
+}
+
+// Sign возвращает подпись тела в формате заголовка SignatureHeader
+func Sign(body []byte, secret string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func render(hook config.WebhookConfig, event Event) (string, error) {
+	text, ok := hook.Templates[event.Type]
+	if !ok {
+		text = defaultTemplates[event.Type]
+	}
+
+	tmpl, err := template.New(event.Type).Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse %s template: %w", event.Type, err)
+	}
+
+	var buf strings.Builder
+	if err := tmpl.Execute(&buf, event); err != nil {
+		return "", fmt.Errorf("failed to render %s template: %w", event.Type, err)
+	}
+	return strings.TrimSpace(buf.String()), nil
+}
+
+func payload(hook config.WebhookConfig, event Event) ([]byte, error) {
+	switch hook.Format {
+	case "", FormatJSON:
+		return json.Marshal(event)
+	case FormatSlack:
+		return json.Marshal(map[string]string{"text": event.Message})
+	case FormatTelegram:
+		return json.Marshal(map[string]string{"chat_id": hook.ChatID, "text": event.Message})
+	}
+	return nil, fmt.Errorf("неизвестный формат webhook: %q", hook.Format)
+}
+
+func abs(value int) int {
+	if value < 0 {
+		return -value
+	}
+	return value
+}
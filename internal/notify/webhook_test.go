@@ -0,0 +1,207 @@
+package notify
+
+import (
+	"encoding/json"
+	"errors"
+	"hhparser/internal/config"
+	"hhparser/internal/hhparser"
+	"hhparser/internal/storage"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type received struct {
+	body      []byte
+	signature string
+}
+
+// recorder — локальный приёмник webhook, первые failures запросов отвечает 503
+func recorder(t *testing.T, failures int) (*httptest.Server, func() []received) {
+	var (
+		mu    sync.Mutex
+		calls []received
+		count int
+	)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		count++
+		if count <= failures {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		body, _ := io.ReadAll(r.Body)
+		calls = append(calls, received{body: body, signature: r.Header.Get(SignatureHeader)})
+	}))
+	t.Cleanup(server.Close)
+
+	return server, func() []received {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]received(nil), calls...)
+	}
+}
+
+func testNotifier(cfg config.NotifyConfig) *Notifier {
+	n := New(cfg)
+	n.sleep = func(time.Duration) {}
+	return n
+}
+
+func statsFor(day int, golang, python int) storage.Statistics {
+	return storage.Statistics{
+		Date:    time.Date(2026, 2, day, 10, 0, 0, 0, time.UTC),
+		Summary: map[string]int{"Golang": golang, "Python": python},
+		Total:   golang + python,
+	}
+}
+
+func TestNotifier_SuccessAndChangeJSON(t *testing.T) {
+	server, calls := recorder(t, 0)
+
+	n := testNotifier(config.NotifyConfig{
+		ChangeThresholdPercent: 20,
+		Webhooks:               []config.WebhookConfig{{Name: "generic", Url: server.URL, Secret: "s3cret"}},
+	})
+
+	previous := statsFor(14, 400, 3000)
+	require.NoError(t, n.RunSucceeded(statsFor(15, 500, 3100), &previous))
+
+	got := calls()
+	require.Len(t, got, 2)
+
+	var success Event
+	require.NoError(t, json.Unmarshal(got[0].body, &success))
+	assert.Equal(t, EventSuccess, success.Type)
+	assert.Equal(t, "Сбор вакансий за 2026-02-15 завершён: 3600 вакансий", success.Message)
+	assert.Equal(t, Sign(got[0].body, "s3cret"), got[0].signature)
+
+	var change Event
+	require.NoError(t, json.Unmarshal(got[1].body, &change))
+	assert.Equal(t, EventChange, change.Type)
+	require.Len(t, change.Changes, 1, "Python изменился на 3.3% — ниже порога")
+	assert.Equal(t, "Golang", change.Changes[0].Technology)
+	assert.Contains(t, change.Message, "Golang: 400 → 500 (+100, +25.0%)")
+}
+
+func TestNotifier_SuccessWithFailures(t *testing.T) {
+	server, calls := recorder(t, 0)
+	n := testNotifier(config.NotifyConfig{Webhooks: []config.WebhookConfig{{Name: "generic", Url: server.URL}}})
+
+	stats := statsFor(15, 500, 0)
+	stats.Manifest = &storage.Manifest{Failures: 2}
+	require.NoError(t, n.RunSucceeded(stats, nil))
+
+	got := calls()
+	require.Len(t, got, 1)
+	var success Event
+	require.NoError(t, json.Unmarshal(got[0].body, &success))
+	assert.Equal(t, 2, success.Failures)
+	assert.Equal(t, "Сбор вакансий за 2026-02-15 завершён: 500 вакансий, запросов с ошибкой: 2", success.Message)
+}
+
+func TestNotifier_PartialFailureNoChange(t *testing.T) {
+	server, calls := recorder(t, 0)
+	n := testNotifier(config.NotifyConfig{
+		ChangeThresholdPercent: 20,
+		Webhooks:               []config.WebhookConfig{{Name: "generic", Url: server.URL}},
+	})
+
+	// Запрос Golang упал: его ноль не падение 400 → 0, а следующий запуск — не рост с нуля
+	previous := statsFor(14, 400, 3000)
+	failed := statsFor(15, 0, 4000)
+	failed.Manifest = &storage.Manifest{Failures: 1, Queries: []storage.QueryResult{
+		{Technology: "Golang", City: 1, Status: hhparser.QueryFailed},
+		{Technology: "Python", City: 1, Status: hhparser.QueryOK, Count: 4000},
+	}}
+	require.NoError(t, n.RunSucceeded(failed, &previous))
+
+	got := calls()
+	require.Len(t, got, 2)
+	var change Event
+	require.NoError(t, json.Unmarshal(got[1].body, &change))
+	require.Len(t, change.Changes, 1)
+	assert.Equal(t, "Python", change.Changes[0].Technology)
+
+	require.NoError(t, n.RunSucceeded(statsFor(16, 410, 4000), &failed))
+	assert.Len(t, calls(), 3, "только уведомление об успехе")
+}
+
+func TestNotifier_SlackAndTelegramFormats(t *testing.T) {
+	server, calls := recorder(t, 0)
+
+	n := testNotifier(config.NotifyConfig{
+		Webhooks: []config.WebhookConfig{
+			{Name: "slack", Url: server.URL, Format: FormatSlack, Events: []string{EventFailure}},
+			{Name: "tg", Url: server.URL, Format: FormatTelegram, ChatID: "-100", Events: []string{EventFailure},
+				Templates: map[string]string{EventFailure: "Ошибка: {{.Error}}"}},
+			{Name: "ignored", Url: server.URL, Events: []string{EventSuccess}},
+		},
+	})
+
+	require.NoError(t, n.RunFailed(time.Date(2026, 2, 14, 0, 0, 0, 0, time.UTC), errors.New("нет коннекта")))
+
+	got := calls()
+	require.Len(t, got, 2)
+	assert.JSONEq(t, `{"text": "Сбор вакансий за 2026-02-14 завершился ошибкой: нет коннекта"}`, string(got[0].body))
+	assert.JSONEq(t, `{"chat_id": "-100", "text": "Ошибка: нет коннекта"}`, string(got[1].body))
+	assert.Empty(t, got[0].signature)
+}
+
+func TestNotifier_Retries(t *testing.T) {
+	server, calls := recorder(t, 2)
+
+	n := testNotifier(config.NotifyConfig{
+		Webhooks: []config.WebhookConfig{{Name: "flaky", Url: server.URL, Retries: 2}},
+	})
+	require.NoError(t, n.RunSucceeded(statsFor(14, 1, 1), nil))
+	assert.Len(t, calls(), 1)
+
+	server, _ = recorder(t, 5)
+	n = testNotifier(config.NotifyConfig{
+		Webhooks: []config.WebhookConfig{{Name: "down", Url: server.URL, Retries: 1}},
+	})
+	err := n.RunSucceeded(statsFor(14, 1, 1), nil)
+	assert.ErrorContains(t, err, "webhook down: status 503")
+}
+
+func TestNotifier_NoRetryOnClientError(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	n := testNotifier(config.NotifyConfig{
+		Webhooks: []config.WebhookConfig{{Name: "bad", Url: server.URL, Retries: 3}},
+	})
+
+	assert.Error(t, n.RunSucceeded(statsFor(14, 1, 1), nil))
+	assert.Equal(t, 1, requests)
+}
+
+func TestSignificantChanges(t *testing.T) {
+	diff := storage.Compare(
+		storage.Statistics{Summary: map[string]int{"A": 100, "B": 100, "C": 0, "D": 10}},
+		storage.Statistics{Summary: map[string]int{"A": 130, "B": 110, "C": 5, "D": 5}},
+	)
+
+	changes := SignificantChanges(diff, 20, 5)
+
+	var names []string
+	for _, change := range changes {
+		names = append(names, change.Technology)
+	}
+	assert.Equal(t, []string{"A", "C", "D"}, names)
+}
@@ -0,0 +1,15 @@
+package proxy
+
+import "hhparser/internal/metrics"
+
+var (
+	requestsTotal = metrics.NewCounterVec("hh_proxy_requests_total",
+		"Запросы через прокси по итогу: success, failure, blocked", "proxy", "result")
+	available = metrics.NewGaugeVec("hh_proxy_available",
+		"1 — прокси используется, 0 — на паузе после ошибок", "proxy")
+)
+
+func init() {
+	metrics.Default.Register(requestsTotal)
+	metrics.Default.Register(available)
+}
@@ -0,0 +1,279 @@
+// Package proxy распределяет запросы к hh.ru по пулу HTTP/SOCKS5 прокси
+// с учётом их здоровья и собственного ограничения частоты.
+package proxy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hhparser/internal/config"
+	"net/url"
+	"sync"
+	"time"
+)
+
+const (
+	StrategyRoundRobin  = "round_robin"
+	StrategyLeastLoaded = "least_loaded"
+)
+
+// Outcome — итог запроса через прокси
+type Outcome int
+
+const (
+	Success Outcome = iota
+	Failure         // ошибка соединения или 5xx
+	Blocked         // 429, 403 или капча: прокси сразу уходит на паузу
+)
+
+var ErrNoHealthyProxy = errors.New("proxy: все прокси на паузе")
+
+// Proxy — один прокси пула и его состояние
+type Proxy struct {
+	URL      *url.URL
+	interval time.Duration
+
+	active        int
+	requests      int
+	failures      int // подряд
+	nextAt        time.Time
+	cooldownUntil time.Time
+}
+
+// Name — адрес прокси без учётных данных, для логов и метрик
+func (p *Proxy) Name() string {
+	return p.URL.Scheme + "://" + p.URL.Host
+}
+
+type Pool struct {
+	mu       sync.Mutex
+	proxies  []*Proxy
+	strategy string
+	cooldown time.Duration
+	maxFails int
+	next     int
+
+	// Для тестов
+	now   func() time.Time
+	sleep func(context.Context, time.Duration) error
+}
+
+// NewPool создаёт пул по конфигу. Пустой список серверов — ошибка, вызывающий код
+// должен проверять config.ProxyConfig.Enabled.
+func NewPool(cfg config.ProxyConfig) (*Pool, error) {
+	if len(cfg.Servers) == 0 {
+		return nil, fmt.Errorf("proxy: пустой список серверов")
+	}
+
+	pool := &Pool{
+		strategy: cfg.Strategy,
+		cooldown: time.Duration(cfg.CooldownSeconds) * time.Second,
+		maxFails: max(cfg.MaxFailures, 1),
+		now:      time.Now,
+		sleep:    sleepContext,
+	}
+	if pool.strategy == "" {
+		pool.strategy = StrategyRoundRobin
+	}
+	if pool.strategy != StrategyRoundRobin && pool.strategy != StrategyLeastLoaded {
+		return nil, fmt.Errorf("proxy: неизвестная стратегия %q", cfg.Strategy)
+	}
+
+	for _, server := range cfg.Servers {
+		u, err := url.Parse(server.Url)
+		if err != nil {
+			return nil, fmt.Errorf("proxy %s: %w", server.Url, err)
+		}
+		switch u.Scheme {
+		case "http", "https", "socks5", "socks5h":
+		default:
+			return nil, fmt.Errorf("proxy %s: поддерживаются http, https и socks5", u.Redacted())
+		}
+
+		rateLimit := server.RateLimitMs
+		if rateLimit == 0 {
+			rateLimit = cfg.RateLimitMs
+		}
+
+		pool.proxies = append(pool.proxies, &Proxy{
+			URL:      u,
+			interval: time.Duration(rateLimit) * time.Millisecond,
+		})
+	}
+
+	for _, p := range pool.proxies {
+		available.Set(1, p.Name())
+	}
+
+	return pool, nil
+}
+
+// Acquire выбирает здоровый прокси и ждёт, пока его ограничение частоты позволит запрос.
+// Если все прокси на паузе, ждёт окончания ближайшей паузы, пока не отменён ctx.
+// После запроса прокси нужно вернуть через Release.
+func (p *Pool) Acquire(ctx context.Context) (*Proxy, error) {
+	p.mu.Lock()
+	proxy := p.pick()
+	for proxy == nil {
+		wait := p.cooldownLeft()
+		p.mu.Unlock()
+
+		if err := p.sleep(ctx, wait); err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrNoHealthyProxy, err)
+		}
+
+		p.mu.Lock()
+		proxy = p.pick()
+	}
+
+	// Резервируем слот: следующий запрос через этот прокси не раньше чем через interval
+	now := p.now()
+	start := now
+	if proxy.nextAt.After(now) {
+		start = proxy.nextAt
+	}
+	proxy.nextAt = start.Add(proxy.interval)
+	proxy.active++
+	proxy.requests++
+	p.mu.Unlock()
+
+	if wait := start.Sub(now); wait > 0 {
+		if err := p.sleep(ctx, wait); err != nil {
+			p.abandon(proxy)
+			return nil, err
+		}
+	}
+
+	return proxy, nil
+}
+
+// abandon возвращает прокси, через который запрос так и не ушёл или был отменён:
+// итог не учитывается, счётчик ошибок подряд не сбрасывается
+func (p *Pool) abandon(proxy *Proxy) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	proxy.active--
+}
+
+// cooldownLeft — время до окончания ближайшей паузы, вызывается под блокировкой
+func (p *Pool) cooldownLeft() time.Duration {
+	var earliest time.Time
+	for _, proxy := range p.proxies {
+		if earliest.IsZero() || proxy.cooldownUntil.Before(earliest) {
+			earliest = proxy.cooldownUntil
+		}
+	}
+	return earliest.Sub(p.now())
+}
+
+// Release учитывает итог запроса: ошибки подряд или блокировка отправляют прокси на паузу
+func (p *Pool) Release(proxy *Proxy, outcome Outcome) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	proxy.active--
+
+	switch outcome {
+	case Success:
+		proxy.failures = 0
+	case Failure:
+		proxy.failures++
+		if proxy.failures >= p.maxFails {
+			p.pause(proxy)
+		}
+	case Blocked:
+		proxy.failures++
+		p.pause(proxy)
+	}
+}
+
+func (p *Pool) pause(proxy *Proxy) {
+	proxy.cooldownUntil = p.now().Add(p.cooldown)
+	proxy.failures = 0
+	available.Set(0, proxy.Name())
+}
+
+// pick вызывается под блокировкой
+func (p *Pool) pick() *Proxy {
+	now := p.now()
+
+	healthy := func(proxy *Proxy) bool {
+		if proxy.cooldownUntil.IsZero() {
+			return true
+		}
+		if now.Before(proxy.cooldownUntil) {
+			return false
+		}
+		proxy.cooldownUntil = time.Time{}
+		available.Set(1, proxy.Name())
+		return true
+	}
+
+	if p.strategy == StrategyLeastLoaded {
+		var best *Proxy
+		for _, proxy := range p.proxies {
+			if !healthy(proxy) {
+				continue
+			}
+			if best == nil || less(proxy, best) {
+				best = proxy
+			}
+		}
+		return best
+	}
+
+	for i := range p.proxies {
+		proxy := p.proxies[(p.next+i)%len(p.proxies)]
+		if healthy(proxy) {
+			p.next = (p.next + i + 1) % len(p.proxies)
+			return proxy
+		}
+	}
+	return nil
+}
+
+// less: меньше запросов в работе, при равенстве — раньше освободится лимит частоты
+func less(a, b *Proxy) bool {
+	if a.active != b.active {
+		return a.active < b.active
+	}
+	return a.nextAt.Before(b.nextAt)
+}
+
+// Stats — снимок состояния прокси
+type Stats struct {
+	Proxy    string
+	Active   int
+	Requests int
+	Healthy  bool
+}
+
+func (p *Pool) Stats() []Stats {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := p.now()
+	stats := make([]Stats, 0, len(p.proxies))
+	for _, proxy := range p.proxies {
+		stats = append(stats, Stats{
+			Proxy:    proxy.Name(),
+			Active:   proxy.active,
+			Requests: proxy.requests,
+			Healthy:  !now.Before(proxy.cooldownUntil),
+		})
+	}
+	return stats
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
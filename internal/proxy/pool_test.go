@@ -0,0 +1,244 @@
+package proxy
+
+import (
+	"context"
+	"hhparser/internal/config"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeClock struct {
+	now   time.Time
+	slept []time.Duration
+}
+
+func testPool(t *testing.T, cfg config.ProxyConfig) (*Pool, *fakeClock) {
+	pool, err := NewPool(cfg)
+	require.NoError(t, err)
+
+	clock := &fakeClock{now: time.Date(2026, 2, 15, 9, 0, 0, 0, time.UTC)}
+	pool.now = func() time.Time { return clock.now }
+	pool.sleep = func(_ context.Context, d time.Duration) error {
+		clock.slept = append(clock.slept, d)
+		return nil
+	}
+	return pool, clock
+}
+
+func servers(urls ...string) []config.ProxyServerConfig {
+	var result []config.ProxyServerConfig
+	for _, u := range urls {
+		result = append(result, config.ProxyServerConfig{Url: u})
+	}
+	return result
+}
+
+func acquire(t *testing.T, pool *Pool) *Proxy {
+	proxy, err := pool.Acquire(context.Background())
+	require.NoError(t, err)
+	return proxy
+}
+
+func TestPool_RoundRobinAndCooldown(t *testing.T) {
+	pool, clock := testPool(t, config.ProxyConfig{
+		CooldownSeconds: 60,
+		MaxFailures:     2,
+		Servers:         servers("http://user:secret@a:3128", "socks5://b:1080", "http://c:3128"),
+	})
+
+	var names []string
+	for range 4 {
+		proxy := acquire(t, pool)
+		names = append(names, proxy.Name())
+		pool.Release(proxy, Success)
+	}
+	assert.Equal(t, []string{"http://a:3128", "socks5://b:1080", "http://c:3128", "http://a:3128"}, names,
+		"учётные данные не попадают в имя")
+
+	b := acquire(t, pool)
+	pool.Release(b, Failure)
+	assert.Equal(t, "http://c:3128", acquire(t, pool).Name(), "одна ошибка не выключает прокси")
+
+	a := acquire(t, pool)
+	pool.Release(a, Blocked)
+	b = acquire(t, pool)
+	pool.Release(b, Failure)
+
+	// a заблокирован, b получил вторую ошибку подряд — остаётся c
+	assert.Equal(t, "http://c:3128", acquire(t, pool).Name())
+	assert.Equal(t, "http://c:3128", acquire(t, pool).Name())
+	assert.EqualValues(t, 0, available.Value("http://a:3128"))
+
+	clock.now = clock.now.Add(61 * time.Second)
+	assert.Equal(t, "http://a:3128", acquire(t, pool).Name(), "после паузы прокси возвращается")
+	assert.EqualValues(t, 1, available.Value("http://a:3128"))
+}
+
+func TestPool_AllPaused(t *testing.T) {
+	pool, clock := testPool(t, config.ProxyConfig{CooldownSeconds: 60, Servers: servers("http://a:3128", "http://b:3128")})
+	pool.sleep = func(_ context.Context, d time.Duration) error {
+		clock.slept = append(clock.slept, d)
+		clock.now = clock.now.Add(d)
+		return nil
+	}
+
+	pool.Release(acquire(t, pool), Blocked)
+	clock.now = clock.now.Add(10 * time.Second)
+	pool.Release(acquire(t, pool), Blocked)
+
+	a := acquire(t, pool)
+	assert.Equal(t, "http://a:3128", a.Name(), "ждём окончания ближайшей паузы")
+	assert.Equal(t, []time.Duration{50 * time.Second}, clock.slept)
+	pool.Release(a, Blocked)
+
+	pool.sleep = func(ctx context.Context, _ time.Duration) error { return ctx.Err() }
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := pool.Acquire(ctx)
+	assert.ErrorIs(t, err, ErrNoHealthyProxy)
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestPool_CancelKeepsFailures(t *testing.T) {
+	pool, _ := testPool(t, config.ProxyConfig{
+		RateLimitMs:     500,
+		CooldownSeconds: 60,
+		MaxFailures:     2,
+		Servers:         servers("http://a:3128"),
+	})
+
+	pool.Release(acquire(t, pool), Failure)
+
+	pool.sleep = func(ctx context.Context, _ time.Duration) error { return ctx.Err() }
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := pool.Acquire(ctx)
+	require.ErrorIs(t, err, context.Canceled)
+
+	assert.Zero(t, pool.Stats()[0].Active)
+	pool.sleep = func(context.Context, time.Duration) error { return nil }
+	pool.Release(acquire(t, pool), Failure)
+	assert.False(t, pool.Stats()[0].Healthy, "отмена не сбросила счётчик ошибок подряд")
+}
+
+func TestPool_LeastLoaded(t *testing.T) {
+	pool, _ := testPool(t, config.ProxyConfig{
+		Strategy: StrategyLeastLoaded,
+		Servers:  servers("http://a:3128", "http://b:3128"),
+	})
+
+	first := acquire(t, pool)
+	second := acquire(t, pool)
+	assert.NotEqual(t, first.Name(), second.Name(), "второй запрос уходит на свободный прокси")
+
+	pool.Release(second, Success)
+	assert.Equal(t, second.Name(), acquire(t, pool).Name())
+}
+
+func TestPool_RateLimit(t *testing.T) {
+	pool, clock := testPool(t, config.ProxyConfig{
+		RateLimitMs: 500,
+		Servers: []config.ProxyServerConfig{
+			{Url: "http://a:3128"},
+			{Url: "http://b:3128", RateLimitMs: 2000},
+		},
+	})
+
+	for range 4 {
+		pool.Release(acquire(t, pool), Success)
+	}
+	assert.Equal(t, []time.Duration{500 * time.Millisecond, 2 * time.Second}, clock.slept,
+		"повторные запросы через тот же прокси ждут своего интервала")
+}
+
+func TestNewPool_Invalid(t *testing.T) {
+	_, err := NewPool(config.ProxyConfig{})
+	assert.Error(t, err)
+
+	_, err = NewPool(config.ProxyConfig{Servers: servers("ftp://a:21")})
+	assert.Error(t, err)
+
+	_, err = NewPool(config.ProxyConfig{Strategy: "random", Servers: servers("http://a:3128")})
+	assert.Error(t, err)
+}
+
+// forwardProxy — HTTP-прокси, отвечающий сам вместо хоста назначения
+func forwardProxy(t *testing.T, status int, hits *atomic.Int32) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		if !r.URL.IsAbs() {
+			t.Errorf("прокси получил относительный URL %s", r.URL)
+		}
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestTransport(t *testing.T) {
+	var healthyHits, blockedHits atomic.Int32
+	healthy := forwardProxy(t, http.StatusOK, &healthyHits)
+	blocked := forwardProxy(t, http.StatusTooManyRequests, &blockedHits)
+
+	pool, _ := testPool(t, config.ProxyConfig{
+		CooldownSeconds: 60,
+		Servers:         servers(blocked.URL, healthy.URL),
+	})
+	client := &http.Client{Transport: NewTransport(pool, nil)}
+
+	for range 4 {
+		res, err := client.Get("http://hh.example/search/vacancy?text=golang")
+		require.NoError(t, err)
+		res.Body.Close()
+	}
+
+	assert.EqualValues(t, 1, blockedHits.Load(), "после 429 прокси на паузе")
+	assert.EqualValues(t, 3, healthyHits.Load())
+
+	proxyName := "http://" + blocked.Listener.Addr().String()
+	assert.EqualValues(t, 1, requestsTotal.Value(proxyName, "blocked"))
+}
+
+func TestTransport_DetectCaptcha(t *testing.T) {
+	var captchaHits, healthyHits atomic.Int32
+	captcha := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		captchaHits.Add(1)
+		_, _ = w.Write([]byte("<html>captcha-input</html>"))
+	}))
+	t.Cleanup(captcha.Close)
+	healthy := forwardProxy(t, http.StatusOK, &healthyHits)
+
+	pool, _ := testPool(t, config.ProxyConfig{
+		CooldownSeconds: 60,
+		Servers:         servers(captcha.URL, healthy.URL),
+	})
+	transport := NewTransport(pool, nil)
+	transport.Detect = func(status int, body []byte) bool {
+		return strings.Contains(string(body), "captcha-input")
+	}
+	client := &http.Client{Transport: transport}
+
+	res, err := client.Get("http://hh.example/search/vacancy?text=golang")
+	require.NoError(t, err)
+	body, err := io.ReadAll(res.Body)
+	res.Body.Close()
+	require.NoError(t, err)
+	assert.Equal(t, "<html>captcha-input</html>", string(body), "тело ответа доступно вызывающему")
+
+	for range 3 {
+		res, err := client.Get("http://hh.example/search/vacancy?text=golang")
+		require.NoError(t, err)
+		res.Body.Close()
+	}
+
+	assert.EqualValues(t, 1, captchaHits.Load(), "капча с кодом 200 отправляет прокси на паузу")
+	assert.EqualValues(t, 3, healthyHits.Load())
+}
@@ -0,0 +1,111 @@
+package proxy
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// Transport отправляет каждый запрос через прокси из пула и сообщает пулу итог
+type Transport struct {
+	pool *Pool
+	base *http.Transport
+
+	// Detect распознаёт блокировку по телу ответа, например капчу с кодом 200.
+	// nil — только по коду ответа и адресу.
+	Detect func(status int, body []byte) bool
+
+	mu         sync.Mutex
+	transports map[*Proxy]*http.Transport
+}
+
+// NewTransport — base задаёт общие настройки соединений (nil — http.DefaultTransport)
+func NewTransport(pool *Pool, base *http.Transport) *Transport {
+	if base == nil {
+		base = http.DefaultTransport.(*http.Transport)
+	}
+	return &Transport{pool: pool, base: base, transports: make(map[*Proxy]*http.Transport)}
+}
+
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	proxy, err := t.pool.Acquire(req.Context())
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := t.transport(proxy).RoundTrip(req)
+	if err != nil && req.Context().Err() != nil {
+		// Запрос отменён вызывающим, прокси тут ни при чём
+		t.pool.abandon(proxy)
+		return nil, err
+	}
+
+	outcome := Classify(res, err)
+	if outcome == Success && t.Detect != nil {
+		outcome, err = t.detect(res)
+	}
+	t.pool.Release(proxy, outcome)
+	requestsTotal.Inc(proxy.Name(), outcomeLabel(outcome))
+
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// detect читает тело ответа целиком и подменяет его копией, чтобы вызывающий
+// прочитал тот же ответ
+func (t *Transport) detect(res *http.Response) (Outcome, error) {
+	body, err := io.ReadAll(res.Body)
+	res.Body.Close()
+	if err != nil {
+		return Failure, err
+	}
+	res.Body = io.NopCloser(bytes.NewReader(body))
+
+	if t.Detect(res.StatusCode, body) {
+		return Blocked, nil
+	}
+	return Success, nil
+}
+
+// transport — отдельный http.Transport на прокси, чтобы соединения не смешивались
+func (t *Transport) transport(proxy *Proxy) *http.Transport {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	transport, ok := t.transports[proxy]
+	if !ok {
+		transport = t.base.Clone()
+		transport.Proxy = http.ProxyURL(proxy.URL)
+		t.transports[proxy] = transport
+	}
+	return transport
+}
+
+// Classify определяет итог запроса для учёта здоровья прокси
+func Classify(res *http.Response, err error) Outcome {
+	switch {
+	case err != nil:
+		return Failure
+	case res.StatusCode == http.StatusTooManyRequests, res.StatusCode == http.StatusForbidden:
+		return Blocked
+	case strings.Contains(res.Request.URL.Path, "captcha"):
+		return Blocked
+	case res.StatusCode >= http.StatusInternalServerError:
+		return Failure
+	}
+	return Success
+}
+
+func outcomeLabel(outcome Outcome) string {
+	switch outcome {
+	case Failure:
+		return "failure"
+	case Blocked:
+		return "blocked"
+	}
+	return "success"
+}
@@ -0,0 +1,120 @@
+// Package run выполняет один полный сбор: запросы к hh.ru, сохранение статистики
+// и уведомления. Его вызывают команды run и daemon, а также e2e-тесты.
+package run
+
+import (
+	"context"
+	"fmt"
+	"hhparser/internal/config"
+	"hhparser/internal/hhparser"
+	"hhparser/internal/httpcache"
+	"hhparser/internal/notify"
+	"hhparser/internal/storage"
+	"log/slog"
+	"time"
+)
+
+// Version записывается в манифест запуска; cmd подставляет версию сборки
+var Version = "dev"
+
+// Collect выполняет один полный сбор статистики, сохраняет результат и рассылает уведомления.
+// progress может быть nil. Прерванный отменой ctx сбор не сохраняется.
+func Collect(ctx context.Context, cfg *config.Config, progress hhparser.ProgressFunc) (storage.Statistics, error) {
+	startTime := time.Now()
+	runInfo := storage.RunInfo{
+		ID:         storage.NewRunID(startTime),
+		StartedAt:  startTime,
+		Version:    Version,
+		ConfigHash: cfg.Hash(),
+		Source:     source(cfg),
+	}
+	slog.Info("run started",
+		"run_id", runInfo.ID,
+		"cities", len(cfg.Cities),
+		"technologies", len(cfg.Technologies),
+		"dir", cfg.Output.Directory)
+
+	notifier := notify.New(cfg.Notify)
+	failed := func(err error) (storage.Statistics, error) {
+		if notifyErr := notifier.RunFailed(startTime, err); notifyErr != nil {
+			slog.Warn("failed to send notification", "error", notifyErr)
+		}
+		return storage.Statistics{}, err
+	}
+
+	parserConfig, err := hhparser.NewParserConfig(cfg)
+	if err != nil {
+		return failed(err)
+	}
+	parserConfig.Progress = progress
+	parserConfig.Context = ctx
+
+	vacancy, concurrency := hhparser.Collect(parserConfig)
+	runInfo.Concurrency = &concurrency
+	if err := ctx.Err(); err != nil {
+		return storage.Statistics{}, fmt.Errorf("сбор прерван: %w", err)
+	}
+
+	// Запуск без единого ответа не сохраняется: он затёр бы дневной срез нулями
+	failures := 0
+	for _, v := range vacancy {
+		if v.Status == hhparser.QueryFailed {
+			failures++
+		}
+	}
+	if len(vacancy) > 0 && failures == len(vacancy) {
+		return failed(fmt.Errorf("все %d запросов завершились ошибкой", failures))
+	}
+
+	var postings []*hhparser.Posting
+	if cfg.Vacancies.Enabled {
+		if registry, err := storage.LoadRegistry(cfg.Output.Directory); err == nil {
+			parserConfig.Known = registry.Known()
+			parserConfig.Since = registry.Since(cfg.Vacancies.Incremental, startTime)
+		} else {
+			slog.Warn("vacancies registry not loaded", "error", err)
+		}
+
+		var report hhparser.PostingsReport
+		postings, report = hhparser.CollectPostings(parserConfig)
+		runInfo.Postings = &report
+		if err := ctx.Err(); err != nil {
+			return storage.Statistics{}, fmt.Errorf("сбор прерван: %w", err)
+		}
+	}
+	runInfo.FinishedAt = time.Now()
+
+	var previous *storage.Statistics
+	if stats, err := storage.LoadBefore(cfg.Output.Directory, startTime); err == nil {
+		previous = &stats
+	}
+
+	storageConfig := storage.NewStorageConfig(cfg)
+	storageConfig.Run = &runInfo
+	storageConfig.Postings = postings
+
+	stats, err := storage.SaveStatistics(vacancy, storageConfig)
+	if err != nil {
+		return failed(err)
+	}
+
+	if err := notifier.RunSucceeded(stats, previous); err != nil {
+		slog.Warn("failed to send notification", "error", err)
+	}
+
+	slog.Info("run finished",
+		"run_id", runInfo.ID,
+		"total", stats.Total,
+		"failures", stats.Manifest.Failures,
+		"duration", time.Since(startTime))
+
+	return stats, nil
+}
+
+// source описывает, откуда берутся ответы: напрямую с hh.ru или через кеш
+func source(cfg *config.Config) string {
+	if cfg.Parser.Cache.Mode == httpcache.ModeOff {
+		return cfg.Parser.UrlSearchVacancies
+	}
+	return fmt.Sprintf("%s (%s: %s)", cfg.Parser.UrlSearchVacancies, cfg.Parser.Cache.Mode, cfg.Parser.Cache.Dir)
+}
@@ -0,0 +1,67 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCron_Next(t *testing.T) {
+	base := time.Date(2026, 2, 14, 10, 17, 30, 0, time.UTC) // суббота
+
+	tests := []struct {
+		name     string
+		expr     string
+		expected time.Time
+	}{
+		{"каждую минуту", "* * * * *", time.Date(2026, 2, 14, 10, 18, 0, 0, time.UTC)},
+		{"каждый час", "0 * * * *", time.Date(2026, 2, 14, 11, 0, 0, 0, time.UTC)},
+		{"макрос @daily", "@daily", time.Date(2026, 2, 15, 0, 0, 0, 0, time.UTC)},
+		{"шаг", "*/20 * * * *", time.Date(2026, 2, 14, 10, 20, 0, 0, time.UTC)},
+		{"шаг от значения", "5/30 * * * *", time.Date(2026, 2, 14, 10, 35, 0, 0, time.UTC)},
+		{"список и диапазон", "0 9-11,18 * * *", time.Date(2026, 2, 14, 11, 0, 0, 0, time.UTC)},
+		{"будни", "0 9 * * 1-5", time.Date(2026, 2, 16, 9, 0, 0, 0, time.UTC)},
+		{"воскресенье как 7", "0 9 * * 7", time.Date(2026, 2, 15, 9, 0, 0, 0, time.UTC)},
+		{"первое число", "@monthly", time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)},
+		{"день месяца или день недели", "0 0 20 * 1", time.Date(2026, 2, 16, 0, 0, 0, 0, time.UTC)},
+		{"29 февраля", "0 0 29 2 *", time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC)},
+		{"шаг дня месяца и день недели", "0 9 */2 * 1", time.Date(2026, 2, 23, 9, 0, 0, 0, time.UTC)},
+		{"день месяца и шаг дня недели", "0 9 20 * */2", time.Date(2026, 6, 20, 9, 0, 0, 0, time.UTC)},
+		{"шаг дня месяца", "0 9 */2 * *", time.Date(2026, 2, 15, 9, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cron, err := ParseCron(tt.expr)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, cron.Next(base))
+		})
+	}
+}
+
+func TestParseCron_Errors(t *testing.T) {
+	for _, expr := range []string{
+		"",
+		"* * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"*/0 * * * *",
+		"5-1 * * * *",
+		"a * * * *",
+	} {
+		t.Run(expr, func(t *testing.T) {
+			_, err := ParseCron(expr)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestCron_NeverMatches(t *testing.T) {
+	cron, err := ParseCron("0 0 31 2 *")
+	require.NoError(t, err)
+	assert.True(t, cron.Next(time.Now()).IsZero())
+}
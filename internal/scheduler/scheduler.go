@@ -0,0 +1,224 @@
+package scheduler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+var ErrAlreadyRunning = errors.New("scheduler: предыдущий запуск ещё не завершён")
+
+// Job — задача, запускаемая по расписанию
+type Job struct {
+	Name    string
+	Cron    *Cron
+	Jitter  time.Duration
+	CatchUp bool
+	Run     func(ctx context.Context) error
+
+	running atomic.Bool
+}
+
+// Event — уведомление о запуске задачи, используется для вывода в лог
+type Event struct {
+	Job      string
+	Started  time.Time
+	Duration time.Duration
+	CatchUp  bool
+	Err      error
+}
+
+type Scheduler struct {
+	jobs      []*Job
+	stateFile string
+	notify    func(Event)
+
+	mu      sync.Mutex
+	lastRun map[string]time.Time
+	wg      sync.WaitGroup
+
+	// Для тестов
+	now   func() time.Time
+	sleep func(ctx context.Context, d time.Duration) bool
+}
+
+// New создаёт планировщик. stateFile хранит время последних запусков и нужен
+// для догоняющих запусков после простоя; пустая строка отключает сохранение.
+func New(jobs []*Job, stateFile string, notify func(Event)) (*Scheduler, error) {
+	s := &Scheduler{
+		jobs:      jobs,
+		stateFile: stateFile,
+		notify:    notify,
+		lastRun:   make(map[string]time.Time),
+		now:       time.Now,
+		sleep:     sleepContext,
+	}
+
+	if err := s.loadState(); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// Run запускает задачи по расписанию до отмены контекста и дожидается
+// завершения уже начатых запусков.
+func (s *Scheduler) Run(ctx context.Context) {
+	defer s.wg.Wait()
+
+	next := make(map[*Job]time.Time, len(s.jobs))
+	now := s.now()
+
+	for _, job := range s.jobs {
+		last, ok := s.last(job.Name)
+		if job.CatchUp && ok && !job.Cron.Next(last).After(now) {
+			s.start(ctx, job, true)
+		}
+		next[job] = job.Cron.Next(now)
+	}
+
+	for {
+		var (
+			job  *Job
+			when time.Time
+		)
+		for j, t := range next {
+			if job == nil || t.Before(when) {
+				job, when = j, t
+			}
+		}
+		if job == nil || when.IsZero() {
+			return
+		}
+
+		if !s.sleep(ctx, when.Sub(s.now())) {
+			return
+		}
+
+		s.start(ctx, job, false)
+		next[job] = job.Cron.Next(when)
+	}
+}
+
+// start запускает задачу в отдельной горутине; если предыдущий запуск
+// ещё идёт, новый пропускается.
+func (s *Scheduler) start(ctx context.Context, job *Job, catchUp bool) {
+	if !job.running.CompareAndSwap(false, true) {
+		s.emit(Event{Job: job.Name, Started: s.now(), CatchUp: catchUp, Err: ErrAlreadyRunning})
+		return
+	}
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		defer job.running.Store(false)
+
+		if job.Jitter > 0 && !s.sleep(ctx, time.Duration(rand.Int63n(int64(job.Jitter)))) {
+			return
+		}
+
+		started := s.now()
+		err := runJob(ctx, job)
+
+		if err == nil {
+			s.setLast(job.Name, started)
+		}
+		s.emit(Event{Job: job.Name, Started: started, Duration: s.now().Sub(started), CatchUp: catchUp, Err: err})
+	}()
+}
+
+func runJob(ctx context.Context, job *Job) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+
+	return job.Run(ctx)
+}
+
+func (s *Scheduler) emit(event Event) {
+	if s.notify != nil {
+		s.notify(event)
+	}
+}
+
+func (s *Scheduler) last(name string) (time.Time, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	t, ok := s.lastRun[name]
+	return t, ok
+}
+
+func (s *Scheduler) setLast(name string, t time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.lastRun[name] = t
+	if err := s.saveState(); err != nil {
+		s.emit(Event{Job: name, Started: t, Err: err})
+	}
+}
+
+func (s *Scheduler) loadState() error {
+	if s.stateFile == "" {
+		return nil
+	}
+
+	data, err := os.ReadFile(s.stateFile)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to read scheduler state: %w", err)
+	}
+
+	if err := json.Unmarshal(data, &s.lastRun); err != nil {
+		return fmt.Errorf("failed to parse scheduler state: %w", err)
+	}
+	return nil
+}
+
+// saveState вызывается под s.mu
+func (s *Scheduler) saveState() error {
+	if s.stateFile == "" {
+		return nil
+	}
+
+	data, err := json.MarshalIndent(s.lastRun, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(s.stateFile), 0o755); err != nil {
+		return fmt.Errorf("failed to create scheduler state directory: %w", err)
+	}
+	if err := os.WriteFile(s.stateFile, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write scheduler state: %w", err)
+	}
+	return nil
+}
+
+func sleepContext(ctx context.Context, d time.Duration) bool {
+	if d <= 0 {
+		return ctx.Err() == nil
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
@@ -0,0 +1,169 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeClock — виртуальное время: sleep дожидается начатых запусков и сдвигает часы мгновенно
+type fakeClock struct {
+	mu     sync.Mutex
+	now    time.Time
+	until  time.Time
+	settle func()
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Sleep(ctx context.Context, d time.Duration) bool {
+	if c.settle != nil {
+		c.settle()
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.now.Add(d).After(c.until) {
+		return false
+	}
+	if d > 0 {
+		c.now = c.now.Add(d)
+	}
+	return ctx.Err() == nil
+}
+
+func newTestScheduler(t *testing.T, jobs []*Job, stateFile string, clock *fakeClock) (*Scheduler, *[]Event) {
+	var (
+		mu     sync.Mutex
+		events []Event
+	)
+
+	s, err := New(jobs, stateFile, func(e Event) {
+		mu.Lock()
+		defer mu.Unlock()
+		events = append(events, e)
+	})
+	require.NoError(t, err)
+
+	s.now = clock.Now
+	s.sleep = clock.Sleep
+	clock.settle = s.wg.Wait
+	return s, &events
+}
+
+func mustCron(t *testing.T, expr string) *Cron {
+	cron, err := ParseCron(expr)
+	require.NoError(t, err)
+	return cron
+}
+
+func TestScheduler_RunsOnSchedule(t *testing.T) {
+	start := time.Date(2026, 2, 14, 10, 0, 30, 0, time.UTC)
+	clock := &fakeClock{now: start, until: start.Add(3*time.Hour + time.Minute)}
+
+	var mu sync.Mutex
+	runs := 0
+	job := &Job{Name: "hourly", Cron: mustCron(t, "0 * * * *"), Run: func(ctx context.Context) error {
+		mu.Lock()
+		defer mu.Unlock()
+		runs++
+		return nil
+	}}
+
+	s, events := newTestScheduler(t, []*Job{job}, "", clock)
+	s.Run(context.Background())
+
+	assert.Equal(t, 3, runs)
+	assert.Len(t, *events, 3)
+}
+
+func TestScheduler_CatchUpAfterDowntime(t *testing.T) {
+	stateFile := filepath.Join(t.TempDir(), "state.json")
+	start := time.Date(2026, 2, 14, 10, 0, 30, 0, time.UTC)
+
+	// Первый запуск: одна успешная отработка в 11:00
+	clock := &fakeClock{now: start, until: start.Add(time.Hour)}
+	job := &Job{Name: "daily", Cron: mustCron(t, "0 11 * * *"), CatchUp: true, Run: func(ctx context.Context) error { return nil }}
+	s, _ := newTestScheduler(t, []*Job{job}, stateFile, clock)
+	s.Run(context.Background())
+
+	// Простой два дня: при старте должен быть догоняющий запуск
+	restart := start.Add(48 * time.Hour)
+	clock = &fakeClock{now: restart, until: restart}
+	s, events := newTestScheduler(t, []*Job{job}, stateFile, clock)
+	s.Run(context.Background())
+
+	require.Len(t, *events, 1)
+	assert.True(t, (*events)[0].CatchUp)
+	assert.NoError(t, (*events)[0].Err)
+}
+
+func TestScheduler_NoCatchUpWithoutMissedRun(t *testing.T) {
+	stateFile := filepath.Join(t.TempDir(), "state.json")
+	start := time.Date(2026, 2, 14, 10, 59, 30, 0, time.UTC)
+
+	clock := &fakeClock{now: start, until: start.Add(time.Minute)}
+	job := &Job{Name: "daily", Cron: mustCron(t, "0 11 * * *"), CatchUp: true, Run: func(ctx context.Context) error { return nil }}
+	s, _ := newTestScheduler(t, []*Job{job}, stateFile, clock)
+	s.Run(context.Background())
+
+	restart := start.Add(2 * time.Hour)
+	clock = &fakeClock{now: restart, until: restart}
+	s, events := newTestScheduler(t, []*Job{job}, stateFile, clock)
+	s.Run(context.Background())
+
+	assert.Empty(t, *events)
+}
+
+func TestScheduler_SkipIfStillRunning(t *testing.T) {
+	release := make(chan struct{})
+	job := &Job{Name: "slow", Cron: mustCron(t, "* * * * *"), Run: func(ctx context.Context) error {
+		<-release
+		return nil
+	}}
+
+	clock := &fakeClock{now: time.Now()}
+	s, events := newTestScheduler(t, []*Job{job}, "", clock)
+
+	s.start(context.Background(), job, false)
+	s.start(context.Background(), job, false)
+	close(release)
+	s.wg.Wait()
+
+	require.Len(t, *events, 2)
+	assert.ErrorIs(t, (*events)[0].Err, ErrAlreadyRunning)
+	assert.NoError(t, (*events)[1].Err)
+}
+
+func TestScheduler_RecoversPanicAndError(t *testing.T) {
+	clock := &fakeClock{now: time.Now()}
+	panicking := &Job{Name: "panic", Cron: mustCron(t, "* * * * *"), Run: func(ctx context.Context) error {
+		panic("нет коннекта")
+	}}
+	failing := &Job{Name: "fail", Cron: mustCron(t, "* * * * *"), Run: func(ctx context.Context) error {
+		return errors.New("boom")
+	}}
+
+	s, events := newTestScheduler(t, []*Job{panicking, failing}, "", clock)
+	s.start(context.Background(), panicking, false)
+	s.wg.Wait()
+	s.start(context.Background(), failing, false)
+	s.wg.Wait()
+
+	require.Len(t, *events, 2)
+	assert.ErrorContains(t, (*events)[0].Err, "нет коннекта")
+	assert.EqualError(t, (*events)[1].Err, "boom")
+
+	_, ok := s.last("fail")
+	assert.False(t, ok, "неуспешный запуск не должен считаться выполненным")
+}
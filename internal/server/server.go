@@ -0,0 +1,305 @@
+package server
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hhparser/internal/config"
+	"hhparser/internal/storage"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	defaultLimit = 100
+	maxLimit     = 1000
+	dateLayout   = "2006-01-02"
+)
+
+type ServerConfig struct {
+	Listen       string
+	DataDir      string
+	Cities       []config.CityConfig
+	Technologies []config.TechnologyConfig
+}
+
+func NewServerConfig(cfg *config.Config) ServerConfig {
+	return ServerConfig{
+		Listen:       cfg.Server.Listen,
+		DataDir:      cfg.Output.Directory,
+		Cities:       cfg.Cities,
+		Technologies: cfg.Technologies,
+	}
+}
+
+type Server struct {
+	cfg ServerConfig
+	mux *http.ServeMux
+}
+
+// Page — страница результатов списочных методов
+type Page[T any] struct {
+	Items  []T `json:"items"`
+	Total  int `json:"total"`
+	Limit  int `json:"limit"`
+	Offset int `json:"offset"`
+}
+
+type apiError struct {
+	Error string `json:"error"`
+}
+
+func New(cfg ServerConfig) *Server {
+	s := &Server{cfg: cfg, mux: http.NewServeMux()}
+
+	s.mux.HandleFunc("GET /api/runs", s.handleRuns)
+	s.mux.HandleFunc("GET /api/stats/latest", s.handleLatest)
+	s.mux.HandleFunc("GET /api/stats/{date}", s.handleStatsByDate)
+	s.mux.HandleFunc("GET /api/series", s.handleSeries)
+	s.mux.HandleFunc("GET /api/diff", s.handleDiff)
+	s.mux.HandleFunc("GET /api/config", s.handleConfig)
+	s.mux.Handle("GET /", dashboardHandler())
+
+	return s
+}
+
+// Handle добавляет обработчик, например /metrics
+func (s *Server) Handle(pattern string, handler http.Handler) {
+	s.mux.Handle(pattern, handler)
+}
+
+func (s *Server) Handler() http.Handler {
+	return s.mux
+}
+
+func (s *Server) handleRuns(w http.ResponseWriter, r *http.Request) {
+	dates, err := storage.ListRuns(s.cfg.DataDir)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	// Новые запуски первыми
+	runs := make([]string, 0, len(dates))
+	for i := len(dates) - 1; i >= 0; i-- {
+		runs = append(runs, dates[i].Format(dateLayout))
+	}
+
+	page, err := paginate(r, runs)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	writeJSON(w, r, page)
+}
+
+func (s *Server) handleLatest(w http.ResponseWriter, r *http.Request) {
+	stats, err := storage.LoadLatest(s.cfg.DataDir)
+	if err != nil {
+		writeStorageError(w, err)
+		return
+	}
+
+	writeJSON(w, r, stats)
+}
+
+func (s *Server) handleStatsByDate(w http.ResponseWriter, r *http.Request) {
+	date, err := time.Parse(dateLayout, r.PathValue("date"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("date должна быть в формате YYYY-MM-DD"))
+		return
+	}
+
+	stats, err := storage.LoadStatistics(s.cfg.DataDir, date)
+	if err != nil {
+		writeStorageError(w, err)
+		return
+	}
+
+	writeJSON(w, r, stats)
+}
+
+func (s *Server) handleSeries(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	technology := query.Get("technology")
+	if technology == "" {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("нужен параметр technology"))
+		return
+	}
+
+	from, to, err := parseRange(query.Get("from"), query.Get("to"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	history, err := storage.LoadHistory(s.cfg.DataDir, from, to)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	page, err := paginate(r, storage.Series(history, technology, query.Get("city")))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	writeJSON(w, r, page)
+}
+
+func (s *Server) handleDiff(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	fromDate, err := time.Parse(dateLayout, query.Get("from"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("from должна быть в формате YYYY-MM-DD"))
+		return
+	}
+
+	var to storage.Statistics
+	if query.Get("to") == "" {
+		to, err = storage.LoadLatest(s.cfg.DataDir)
+	} else {
+		var toDate time.Time
+		toDate, err = time.Parse(dateLayout, query.Get("to"))
+		if err != nil {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("to должна быть в формате YYYY-MM-DD"))
+			return
+		}
+		to, err = storage.LoadStatistics(s.cfg.DataDir, toDate)
+	}
+	if err != nil {
+		writeStorageError(w, err)
+		return
+	}
+
+	from, err := storage.LoadStatistics(s.cfg.DataDir, fromDate)
+	if err != nil {
+		writeStorageError(w, err)
+		return
+	}
+
+	writeJSON(w, r, storage.Compare(from, to))
+}
+
+func (s *Server) handleConfig(w http.ResponseWriter, r *http.Request) {
+	type technology struct {
+		Key      string               `json:"key"`
+		Name     string               `json:"name"`
+		Category string               `json:"category"`
+		Filters  *config.FilterConfig `json:"filters,omitempty"`
+	}
+	type city struct {
+		Name string `json:"name"`
+		Code int    `json:"code"`
+	}
+
+	response := struct {
+		Cities       []city       `json:"cities"`
+		Technologies []technology `json:"technologies"`
+	}{}
+
+	for _, c := range s.cfg.Cities {
+		response.Cities = append(response.Cities, city{Name: c.Name, Code: c.Code})
+	}
+	for _, t := range s.cfg.Technologies {
+		response.Technologies = append(response.Technologies, technology{
+			Key:      t.Key(),
+			Name:     t.Name,
+			Category: t.Category,
+			Filters:  t.Filters,
+		})
+	}
+
+	writeJSON(w, r, response)
+}
+
+func parseRange(fromValue, toValue string) (time.Time, time.Time, error) {
+	var from, to time.Time
+	var err error
+
+	if fromValue != "" {
+		if from, err = time.Parse(dateLayout, fromValue); err != nil {
+			return from, to, fmt.Errorf("from должна быть в формате YYYY-MM-DD")
+		}
+	}
+	if toValue != "" {
+		if to, err = time.Parse(dateLayout, toValue); err != nil {
+			return from, to, fmt.Errorf("to должна быть в формате YYYY-MM-DD")
+		}
+	}
+
+	return from, to, nil
+}
+
+func paginate[T any](r *http.Request, items []T) (Page[T], error) {
+	page := Page[T]{Total: len(items), Limit: defaultLimit}
+
+	query := r.URL.Query()
+	if value := query.Get("limit"); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit <= 0 || limit > maxLimit {
+			return page, fmt.Errorf("limit должен быть от 1 до %d", maxLimit)
+		}
+		page.Limit = limit
+	}
+	if value := query.Get("offset"); value != "" {
+		offset, err := strconv.Atoi(value)
+		if err != nil || offset < 0 {
+			return page, fmt.Errorf("offset должен быть >= 0")
+		}
+		page.Offset = offset
+	}
+
+	start := min(page.Offset, len(items))
+	end := min(start+page.Limit, len(items))
+	page.Items = items[start:end]
+
+	return page, nil
+}
+
+// writeJSON отдаёт ответ с ETag и отвечает 304, если клиент прислал совпадающий If-None-Match
+func writeJSON(w http.ResponseWriter, r *http.Request, value any) {
+	var body bytes.Buffer
+	encoder := json.NewEncoder(&body)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(value); err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	sum := sha256.Sum256(body.Bytes())
+	etag := `"` + hex.EncodeToString(sum[:16]) + `"`
+
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Cache-Control", "no-cache")
+
+	if match := r.Header.Get("If-None-Match"); match == etag || match == "*" {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	_, _ = w.Write(body.Bytes())
+}
+
+func writeStorageError(w http.ResponseWriter, err error) {
+	if errors.Is(err, storage.ErrRunNotFound) {
+		writeError(w, http.StatusNotFound, err)
+		return
+	}
+	writeError(w, http.StatusInternalServerError, err)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(apiError{Error: err.Error()})
+}
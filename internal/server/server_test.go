@@ -0,0 +1,176 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"hhparser/internal/config"
+	"hhparser/internal/storage"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeRun(t *testing.T, dir string, day, golang int) {
+	stats := storage.Statistics{
+		Date:    time.Date(2026, 2, day, 10, 0, 0, 0, time.UTC),
+		Cities:  []storage.CityStatistics{{Name: "MOSCOW", Code: 1, Vacancies: map[string]int{"Golang": golang}, Total: golang}},
+		Summary: map[string]int{"Golang": golang},
+		Total:   golang,
+	}
+
+	data, err := json.Marshal(stats)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, fmt.Sprintf("2026-02-%02d.json", day)), data, 0o644))
+}
+
+func newTestServer(t *testing.T) *httptest.Server {
+	dir := t.TempDir()
+	writeRun(t, dir, 14, 400)
+	writeRun(t, dir, 15, 410)
+	writeRun(t, dir, 16, 390)
+
+	s := New(ServerConfig{
+		DataDir: dir,
+		Cities:  []config.CityConfig{{Name: "MOSCOW", Code: 1}},
+		Technologies: []config.TechnologyConfig{
+			{Name: "Golang", Category: "languages"},
+			{Name: "Golang", Filters: &config.FilterConfig{Schedule: "remote"}},
+		},
+	})
+
+	server := httptest.NewServer(s.Handler())
+	t.Cleanup(server.Close)
+	return server
+}
+
+func getJSON(t *testing.T, url string, target any) *http.Response {
+	res, err := http.Get(url)
+	require.NoError(t, err)
+	defer res.Body.Close()
+
+	if target != nil && res.StatusCode == http.StatusOK {
+		require.NoError(t, json.NewDecoder(res.Body).Decode(target))
+	}
+	return res
+}
+
+func TestServer_Latest(t *testing.T) {
+	server := newTestServer(t)
+
+	var stats storage.Statistics
+	res := getJSON(t, server.URL+"/api/stats/latest", &stats)
+
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	assert.Equal(t, 390, stats.Summary["Golang"])
+	assert.NotEmpty(t, res.Header.Get("ETag"))
+}
+
+func TestServer_StatsByDate(t *testing.T) {
+	server := newTestServer(t)
+
+	var stats storage.Statistics
+	res := getJSON(t, server.URL+"/api/stats/2026-02-15", &stats)
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	assert.Equal(t, 410, stats.Total)
+
+	res = getJSON(t, server.URL+"/api/stats/2026-03-01", nil)
+	assert.Equal(t, http.StatusNotFound, res.StatusCode)
+
+	res = getJSON(t, server.URL+"/api/stats/yesterday", nil)
+	assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+}
+
+func TestServer_SeriesPagination(t *testing.T) {
+	server := newTestServer(t)
+
+	var page Page[storage.Point]
+	res := getJSON(t, server.URL+"/api/series?technology=Golang&city=MOSCOW&limit=2&offset=1", &page)
+
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	assert.Equal(t, 3, page.Total)
+	assert.Equal(t, []storage.Point{{Date: "2026-02-15", Count: 410}, {Date: "2026-02-16", Count: 390}}, page.Items)
+
+	res = getJSON(t, server.URL+"/api/series?technology=Golang&limit=0", nil)
+	assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+
+	res = getJSON(t, server.URL+"/api/series", nil)
+	assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+}
+
+func TestServer_RunsNewestFirst(t *testing.T) {
+	server := newTestServer(t)
+
+	var page Page[string]
+	getJSON(t, server.URL+"/api/runs", &page)
+
+	assert.Equal(t, []string{"2026-02-16", "2026-02-15", "2026-02-14"}, page.Items)
+}
+
+func TestServer_Diff(t *testing.T) {
+	server := newTestServer(t)
+
+	var diff storage.Diff
+	res := getJSON(t, server.URL+"/api/diff?from=2026-02-14", &diff)
+
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	assert.Equal(t, "2026-02-16", diff.To)
+	require.Len(t, diff.Technologies, 1)
+	assert.Equal(t, -10, diff.Technologies[0].Delta)
+}
+
+func TestServer_Config(t *testing.T) {
+	server := newTestServer(t)
+
+	var response struct {
+		Technologies []struct {
+			Key string `json:"key"`
+		} `json:"technologies"`
+	}
+	getJSON(t, server.URL+"/api/config", &response)
+
+	require.Len(t, response.Technologies, 2)
+	assert.Equal(t, "Golang[schedule=remote]", response.Technologies[1].Key)
+}
+
+func TestServer_ETagNotModified(t *testing.T) {
+	server := newTestServer(t)
+
+	res := getJSON(t, server.URL+"/api/stats/latest", nil)
+	etag := res.Header.Get("ETag")
+
+	req, err := http.NewRequest(http.MethodGet, server.URL+"/api/stats/latest", nil)
+	require.NoError(t, err)
+	req.Header.Set("If-None-Match", etag)
+
+	res, err = http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	res.Body.Close()
+
+	assert.Equal(t, http.StatusNotModified, res.StatusCode)
+}
+
+func TestServer_Dashboard(t *testing.T) {
+	server := newTestServer(t)
+
+	for _, path := range []string{"/", "/app.js", "/style.css"} {
+		res, err := http.Get(server.URL + path)
+		require.NoError(t, err)
+		body, err := io.ReadAll(res.Body)
+		res.Body.Close()
+		require.NoError(t, err)
+
+		assert.Equal(t, http.StatusOK, res.StatusCode, path)
+		// Дашборд должен работать без сети: никаких внешних скриптов и стилей
+		assert.NotRegexp(t, `(src|href)="https?://`, string(body), path)
+	}
+
+	res := getJSON(t, server.URL+"/missing.js", nil)
+	assert.Equal(t, http.StatusNotFound, res.StatusCode)
+}
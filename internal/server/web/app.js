@@ -0,0 +1,252 @@
+"use strict";
+
+// Дашборд работает только с API этого же сервера, без внешних библиотек
+
+const palette = ["#1f77b4", "#ff7f0e", "#2ca02c", "#d62728", "#9467bd",
+  "#8c564b", "#e377c2", "#7f7f7f", "#bcbd22", "#17becf"];
+
+const state = {
+  config: { cities: [], technologies: [] },
+  stats: null,
+  selectedCities: new Set(),
+};
+
+const $ = (id) => document.getElementById(id);
+
+async function api(path) {
+  const res = await fetch(path);
+  if (!res.ok) {
+    throw new Error(`${path}: ${res.status}`);
+  }
+  return res.json();
+}
+
+function el(tag, attrs = {}, children = []) {
+  const ns = ["svg", "line", "polyline", "rect", "text", "circle", "g", "title"].includes(tag)
+    ? "http://www.w3.org/2000/svg" : null;
+  const node = ns ? document.createElementNS(ns, tag) : document.createElement(tag);
+  for (const [key, value] of Object.entries(attrs)) {
+    if (key === "text") {
+      node.textContent = value;
+    } else {
+      node.setAttribute(key, value);
+    }
+  }
+  for (const child of children) {
+    node.appendChild(child);
+  }
+  return node;
+}
+
+function option(value, label) {
+  return el("option", { value, text: label ?? value });
+}
+
+function visibleTechnologies() {
+  const category = $("category").value;
+  return state.config.technologies.filter((t) => !category || (t.category || "other") === category);
+}
+
+// Тепловая карта: цвет ячейки пропорционален доле от максимума в строке
+function renderHeatmap() {
+  const root = $("heatmap");
+  root.replaceChildren();
+
+  if (!state.stats) {
+    root.appendChild(el("p", { class: "empty", text: "Нет данных" }));
+    return;
+  }
+
+  const cities = state.stats.cities || [];
+  const header = el("tr", {}, [el("th", { text: "Технология" }),
+    ...cities.map((c) => el("th", { text: c.name })), el("th", { text: "ВСЕГО" })]);
+  const table = el("table", { class: "heatmap" }, [header]);
+
+  for (const tech of visibleTechnologies()) {
+    const counts = cities.map((c) => (c.vacancies || {})[tech.key] || 0);
+    const max = Math.max(1, ...counts);
+    const row = el("tr", {}, [el("td", { text: tech.key })]);
+    counts.forEach((count) => {
+      const alpha = (count / max).toFixed(2);
+      row.appendChild(el("td", { text: count, style: `background: rgba(31, 119, 180, ${alpha * 0.6})` }));
+    });
+    row.appendChild(el("td", { class: "total", text: (state.stats.summary || {})[tech.key] || 0 }));
+    table.appendChild(row);
+  }
+
+  root.appendChild(table);
+}
+
+function renderCityCheckboxes() {
+  const root = $("cities");
+  root.replaceChildren();
+
+  state.config.cities.forEach((city) => {
+    const input = el("input", { type: "checkbox", value: city.name });
+    input.checked = state.selectedCities.has(city.name);
+    input.addEventListener("change", () => {
+      if (input.checked) {
+        state.selectedCities.add(city.name);
+      } else {
+        state.selectedCities.delete(city.name);
+      }
+      renderTimeseries();
+      renderComparison();
+    });
+    root.appendChild(el("label", {}, [input, document.createTextNode(" " + city.name)]));
+  });
+}
+
+function lineChart(series, width = 900, height = 300) {
+  const pad = { left: 50, right: 16, top: 10, bottom: 30 };
+  const dates = [...new Set(series.flatMap((s) => s.points.map((p) => p.date)))].sort();
+  const max = Math.max(1, ...series.flatMap((s) => s.points.map((p) => p.count)));
+
+  const x = (date) => pad.left + (dates.length < 2 ? 0 :
+    dates.indexOf(date) * (width - pad.left - pad.right) / (dates.length - 1));
+  const y = (count) => height - pad.bottom - count * (height - pad.top - pad.bottom) / max;
+
+  const svg = el("svg", { viewBox: `0 0 ${width} ${height}`, width: "100%" });
+
+  for (let i = 0; i <= 4; i++) {
+    const value = Math.round(max * i / 4);
+    svg.appendChild(el("line", { class: "grid", x1: pad.left, x2: width - pad.right, y1: y(value), y2: y(value) }));
+    svg.appendChild(el("text", { x: 4, y: y(value) + 4, text: value }));
+  }
+
+  const step = Math.max(1, Math.ceil(dates.length / 8));
+  dates.forEach((date, i) => {
+    if (i % step === 0) {
+      svg.appendChild(el("text", { x: x(date) - 28, y: height - 8, text: date }));
+    }
+  });
+
+  series.forEach((s, i) => {
+    const color = palette[i % palette.length];
+    const points = s.points.map((p) => `${x(p.date)},${y(p.count)}`).join(" ");
+    svg.appendChild(el("polyline", { points, fill: "none", stroke: color, "stroke-width": 2 }));
+    s.points.forEach((p) => {
+      svg.appendChild(el("circle", { cx: x(p.date), cy: y(p.count), r: 3, fill: color },
+        [el("title", { text: `${s.name} ${p.date}: ${p.count}` })]));
+    });
+  });
+
+  return svg;
+}
+
+function legend(names) {
+  return el("div", { class: "legend" }, names.map((name, i) =>
+    el("span", { text: name, style: `--color: ${palette[i % palette.length]}` })));
+}
+
+async function renderTimeseries() {
+  const root = $("timeseries");
+  const technology = $("technology").value;
+  if (!technology) {
+    root.replaceChildren(el("p", { class: "empty", text: "Нет технологий" }));
+    return;
+  }
+
+  const cities = [...state.selectedCities];
+  const requests = cities.length ? cities : [""];
+  const series = await Promise.all(requests.map(async (city) => {
+    const params = new URLSearchParams({ technology, limit: 1000 });
+    if (city) {
+      params.set("city", city);
+    }
+    const page = await api(`/api/series?${params}`);
+    return { name: city || "Все города", points: page.items };
+  }));
+
+  root.replaceChildren(lineChart(series), legend(series.map((s) => s.name)));
+}
+
+// Сравнение выбранных городов: горизонтальные столбцы по технологиям выбранной категории
+function renderComparison() {
+  const root = $("comparison");
+  root.replaceChildren();
+
+  if (!state.stats) {
+    return;
+  }
+
+  const cities = (state.stats.cities || []).filter((c) =>
+    state.selectedCities.size === 0 || state.selectedCities.has(c.name));
+  const techs = visibleTechnologies();
+  const max = Math.max(1, ...techs.flatMap((t) => cities.map((c) => (c.vacancies || {})[t.key] || 0)));
+
+  const barHeight = 12;
+  const rowHeight = barHeight * cities.length + 10;
+  const width = 900;
+  const labelWidth = 180;
+  const svg = el("svg", { viewBox: `0 0 ${width} ${rowHeight * techs.length + 10}`, width: "100%" });
+
+  techs.forEach((tech, row) => {
+    const top = row * rowHeight + 5;
+    svg.appendChild(el("text", { x: 0, y: top + rowHeight / 2, text: tech.key }));
+    cities.forEach((city, i) => {
+      const count = (city.vacancies || {})[tech.key] || 0;
+      const barWidth = count * (width - labelWidth - 60) / max;
+      svg.appendChild(el("rect", {
+        x: labelWidth, y: top + i * barHeight, width: barWidth, height: barHeight - 2,
+        fill: palette[i % palette.length],
+      }, [el("title", { text: `${city.name}: ${count}` })]));
+      svg.appendChild(el("text", { x: labelWidth + barWidth + 4, y: top + i * barHeight + barHeight - 3, text: count }));
+    });
+  });
+
+  root.appendChild(svg);
+  root.appendChild(legend(cities.map((c) => c.name)));
+}
+
+async function loadRun() {
+  const date = $("run").value;
+  try {
+    state.stats = await api(date ? `/api/stats/${date}` : "/api/stats/latest");
+  } catch (e) {
+    state.stats = null;
+  }
+  renderHeatmap();
+  renderComparison();
+}
+
+function fillTechnologies() {
+  const select = $("technology");
+  const current = select.value;
+  select.replaceChildren(...visibleTechnologies().map((t) => option(t.key)));
+  if ([...select.options].some((o) => o.value === current)) {
+    select.value = current;
+  }
+}
+
+async function init() {
+  state.config = await api("/api/config");
+  state.config.cities = state.config.cities || [];
+  state.config.technologies = state.config.technologies || [];
+
+  const categories = [...new Set(state.config.technologies.map((t) => t.category || "other"))];
+  $("category").append(...categories.map((c) => option(c)));
+
+  const runs = await api("/api/runs?limit=1000");
+  $("run").replaceChildren(...runs.items.map((date) => option(date)));
+
+  state.config.cities.slice(0, 3).forEach((c) => state.selectedCities.add(c.name));
+
+  $("run").addEventListener("change", loadRun);
+  $("category").addEventListener("change", () => {
+    fillTechnologies();
+    renderHeatmap();
+    renderComparison();
+    renderTimeseries();
+  });
+  $("technology").addEventListener("change", renderTimeseries);
+
+  fillTechnologies();
+  renderCityCheckboxes();
+  await loadRun();
+  await renderTimeseries();
+}
+
+init().catch((e) => {
+  document.querySelector("main").prepend(el("p", { class: "empty", text: `Ошибка загрузки: ${e.message}` }));
+});
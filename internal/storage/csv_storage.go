@@ -0,0 +1,36 @@
+package storage
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+)
+
+// WriteCSV выводит статистику в CSV: строка на технологию, колонка на город и итог
+func WriteCSV(out io.Writer, stats Statistics) error {
+	w := csv.NewWriter(out)
+
+	header := []string{"technology", "category"}
+	for _, city := range stats.Cities {
+		header = append(header, city.Name)
+	}
+	header = append(header, "total")
+	if err := w.Write(header); err != nil {
+		return err
+	}
+
+	for _, tech := range stats.Technologies {
+		key := tech.Key()
+		row := []string{key, tech.Category}
+		for _, city := range stats.Cities {
+			row = append(row, strconv.Itoa(city.Vacancies[key]))
+		}
+		row = append(row, strconv.Itoa(stats.Summary[key]))
+		if err := w.Write(row); err != nil {
+			return err
+		}
+	}
+
+	w.Flush()
+	return w.Error()
+}
@@ -0,0 +1,100 @@
+package storage
+
+import (
+	"math"
+	"sort"
+)
+
+type Diff struct {
+	From         string           `json:"from"`
+	To           string           `json:"to"`
+	Total        Change           `json:"total"`
+	Technologies []TechnologyDiff `json:"technologies"`
+
+	// Запуски сделаны с разными конфигами (по хешу из манифеста)
+	ConfigChanged bool `json:"configChanged,omitempty"`
+}
+
+type TechnologyDiff struct {
+	Technology string            `json:"technology"`
+	Change                       // Сумма по всем городам
+	Cities     map[string]Change `json:"cities"`
+}
+
+type Change struct {
+	From    int     `json:"from"`
+	To      int     `json:"to"`
+	Delta   int     `json:"delta"`
+	Percent float64 `json:"percent"`
+}
+
+func newChange(from, to int) Change {
+	change := Change{From: from, To: to, Delta: to - from}
+	if from != 0 {
+		change.Percent = math.Round(float64(change.Delta)*10000/float64(from)) / 100
+	}
+	return change
+}
+
+// Compare сравнивает два запуска по всем технологиям и городам, встречающимся хотя бы в одном из них
+func Compare(from, to Statistics) Diff {
+	diff := Diff{
+		From:  from.Date.Format(dateLayout),
+		To:    to.Date.Format(dateLayout),
+		Total: newChange(from.Total, to.Total),
+	}
+
+	if from.Manifest != nil && to.Manifest != nil {
+		diff.ConfigChanged = from.Manifest.ConfigHash != to.Manifest.ConfigHash
+	}
+
+	for _, key := range unionKeys(from.Summary, to.Summary) {
+		techDiff := TechnologyDiff{
+			Technology: key,
+			Change:     newChange(from.Summary[key], to.Summary[key]),
+			Cities:     make(map[string]Change),
+		}
+
+		for _, city := range unionCities(from, to) {
+			before, _ := from.Count(key, city)
+			after, _ := to.Count(key, city)
+			techDiff.Cities[city] = newChange(before, after)
+		}
+
+		diff.Technologies = append(diff.Technologies, techDiff)
+	}
+
+	return diff
+}
+
+func unionKeys(a, b map[string]int) []string {
+	seen := make(map[string]bool, len(a)+len(b))
+	for key := range a {
+		seen[key] = true
+	}
+	for key := range b {
+		seen[key] = true
+	}
+
+	keys := make([]string, 0, len(seen))
+	for key := range seen {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+func unionCities(a, b Statistics) []string {
+	seen := make(map[string]bool)
+	var cities []string
+	for _, stats := range []Statistics{a, b} {
+		for _, city := range stats.Cities {
+			if !seen[city.Name] {
+				seen[city.Name] = true
+				cities = append(cities, city.Name)
+			}
+		}
+	}
+	return cities
+}
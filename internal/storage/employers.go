@@ -0,0 +1,208 @@
+package storage
+
+import (
+	"fmt"
+	"hhparser/internal/config"
+	"hhparser/internal/hhparser"
+	"io"
+	"slices"
+	"strings"
+)
+
+// EmployerStatistics — крупнейшие работодатели технологии в городе.
+// City пустой и CityCode 0 — по всем городам вместе.
+type EmployerStatistics struct {
+	Technology string         `json:"technology"`
+	City       string         `json:"city,omitempty"`
+	CityCode   int            `json:"cityCode,omitempty"`
+	Vacancies  int            `json:"vacancies"` // вакансий после чёрного и белого списков
+	Employers  []EmployerStat `json:"employers"`
+}
+
+type EmployerStat struct {
+	ID        string       `json:"id,omitempty"`
+	Name      string       `json:"name"`
+	Vacancies int          `json:"vacancies"`
+	Share     float64      `json:"share"` // % вакансий технологии в городе
+	Salary    *SalaryRange `json:"salary,omitempty"`
+}
+
+// SalaryRange — разброс зарплат в вакансиях работодателя в самой частой у него валюте:
+// от наименьшей нижней до наибольшей верхней границы
+type SalaryRange struct {
+	Currency  string `json:"currency"`
+	From      int    `json:"from"`
+	To        int    `json:"to"`
+	Vacancies int    `json:"vacancies"` // вакансий с зарплатой в этой валюте
+}
+
+// employerFilter отбирает работодателей по id или названию без учёта регистра
+type employerFilter struct {
+	blacklist map[string]bool
+	whitelist map[string]bool
+}
+
+func newEmployerFilter(cfg config.EmployersConfig) employerFilter {
+	set := func(values []string) map[string]bool {
+		result := make(map[string]bool, len(values))
+		for _, value := range values {
+			result[strings.ToLower(strings.TrimSpace(value))] = true
+		}
+		return result
+	}
+	return employerFilter{blacklist: set(cfg.Blacklist), whitelist: set(cfg.Whitelist)}
+}
+
+func (f employerFilter) allowed(employer hhparser.Employer) bool {
+	id, name := strings.ToLower(employer.ID), strings.ToLower(employer.Name)
+	if f.blacklist[name] || (id != "" && f.blacklist[id]) {
+		return false
+	}
+	if len(f.whitelist) == 0 {
+		return true
+	}
+	return f.whitelist[name] || (id != "" && f.whitelist[id])
+}
+
+// employerAccumulator собирает вакансии одного работодателя
+type employerAccumulator struct {
+	stat     EmployerStat
+	salaries map[string]*SalaryRange
+}
+
+func (a *employerAccumulator) add(salary *hhparser.Salary) {
+	a.stat.Vacancies++
+	if salary == nil || (salary.From == 0 && salary.To == 0) {
+		return
+	}
+
+	from, to := salary.From, salary.To
+	if from == 0 {
+		from = to
+	}
+	if to == 0 {
+		to = from
+	}
+
+	r := a.salaries[salary.Currency]
+	if r == nil {
+		a.salaries[salary.Currency] = &SalaryRange{Currency: salary.Currency, From: from, To: to, Vacancies: 1}
+		return
+	}
+	r.From, r.To = min(r.From, from), max(r.To, to)
+	r.Vacancies++
+}
+
+// salary возвращает разброс в валюте, в которой у работодателя больше всего вакансий
+func (a *employerAccumulator) salary() *SalaryRange {
+	var best *SalaryRange
+	for _, r := range a.salaries {
+		if best == nil || r.Vacancies > best.Vacancies ||
+			(r.Vacancies == best.Vacancies && r.Currency < best.Currency) {
+			best = r
+		}
+	}
+	return best
+}
+
+// collectEmployers считает работодателей каждой технологии по городам и по всем городам.
+// Вакансии без работодателя и отсеянные списками из vacancies.employers не учитываются.
+func collectEmployers(postings []*hhparser.Posting, cfg StorageConfig) []EmployerStatistics {
+	filter := newEmployerFilter(cfg.Vacancies.Employers)
+
+	cityNames := make(map[int]string, len(cfg.Cities))
+	for _, city := range cfg.Cities {
+		cityNames[city.Code] = city.Name
+	}
+
+	type key struct {
+		technology string
+		city       int // 0 — все города
+	}
+	totals := make(map[key]int)
+	employers := make(map[key]map[string]*employerAccumulator)
+
+	for _, posting := range postings {
+		employer := posting.Employer
+		if (employer.ID == "" && employer.Name == "") || !filter.allowed(employer) {
+			continue
+		}
+		id := employer.ID
+		if id == "" {
+			id = employer.Name
+		}
+
+		for _, technology := range posting.Technologies {
+			for _, city := range []int{posting.City, 0} {
+				k := key{technology, city}
+				totals[k]++
+				if employers[k] == nil {
+					employers[k] = make(map[string]*employerAccumulator)
+				}
+				acc := employers[k][id]
+				if acc == nil {
+					acc = &employerAccumulator{
+						stat:     EmployerStat{ID: employer.ID, Name: employer.Name},
+						salaries: make(map[string]*SalaryRange),
+					}
+					employers[k][id] = acc
+				}
+				acc.add(posting.Salary)
+			}
+		}
+	}
+
+	var result []EmployerStatistics
+	for _, tech := range cfg.Technologies {
+		for _, city := range append(cityCodes(cfg.Cities), 0) {
+			k := key{tech.Key(), city}
+			if totals[k] == 0 {
+				continue
+			}
+
+			stat := EmployerStatistics{
+				Technology: tech.Key(),
+				City:       cityNames[city],
+				CityCode:   city,
+				Vacancies:  totals[k],
+			}
+			for _, acc := range employers[k] {
+				employer := acc.stat
+				employer.Share = percent(employer.Vacancies, totals[k])
+				employer.Salary = acc.salary()
+				stat.Employers = append(stat.Employers, employer)
+			}
+
+			slices.SortFunc(stat.Employers, func(a, b EmployerStat) int {
+				if a.Vacancies != b.Vacancies {
+					return b.Vacancies - a.Vacancies
+				}
+				return strings.Compare(a.Name, b.Name)
+			})
+			if top := cfg.Vacancies.Employers.Top; top > 0 && len(stat.Employers) > top {
+				stat.Employers = stat.Employers[:top]
+			}
+
+			result = append(result, stat)
+		}
+	}
+
+	return result
+}
+
+func writeEmployersTXT(w io.Writer, stats Statistics) {
+	if len(stats.Employers) == 0 {
+		return
+	}
+
+	fmt.Fprintf(w, "\nКРУПНЕЙШИЕ РАБОТОДАТЕЛИ\n")
+	fmt.Fprintln(w, "Технология\tГород\tВакансий\tРаботодатель\tВакансий\t%")
+	for _, stat := range stats.Employers {
+		if stat.CityCode == 0 || len(stat.Employers) == 0 {
+			continue
+		}
+		top := stat.Employers[0]
+		fmt.Fprintf(w, "%s\t%s\t%d\t%s\t%d\t%.2f\n",
+			stat.Technology, stat.City, stat.Vacancies, top.Name, top.Vacancies, top.Share)
+	}
+}
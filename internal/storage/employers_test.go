@@ -0,0 +1,97 @@
+package storage
+
+import (
+	"bytes"
+	"hhparser/internal/config"
+	"hhparser/internal/hhparser"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func employersPostings() []*hhparser.Posting {
+	yandex := hhparser.Employer{ID: "1740", Name: "Яндекс"}
+	avito := hhparser.Employer{ID: "84585", Name: "Авито"}
+	agency := hhparser.Employer{ID: "999", Name: "Кадровое агентство"}
+	posting := func(city int, employer hhparser.Employer, salary *hhparser.Salary) *hhparser.Posting {
+		return &hhparser.Posting{City: city, Technologies: []string{"Golang"}, Employer: employer, Salary: salary}
+	}
+	rur := func(from, to int) *hhparser.Salary { return &hhparser.Salary{From: from, To: to, Currency: "RUR"} }
+
+	return []*hhparser.Posting{
+		posting(1, yandex, rur(200000, 300000)),
+		posting(1, yandex, rur(150000, 0)),
+		posting(1, yandex, rur(0, 400000)),
+		posting(1, yandex, &hhparser.Salary{From: 5000, To: 6000, Currency: "USD"}),
+		posting(1, avito, nil),
+		posting(1, agency, rur(500000, 900000)),
+		posting(1, agency, nil),
+		posting(1, hhparser.Employer{}, nil),
+		posting(53, yandex, rur(100000, 100000)),
+	}
+}
+
+func employersConfig(employers config.EmployersConfig) StorageConfig {
+	return StorageConfig{
+		Cities:       []config.CityConfig{{Name: "MOSCOW", Code: 1}, {Name: "KRASNODAR", Code: 53}},
+		Technologies: []config.TechnologyConfig{{Name: "Golang", Search: "golang"}, {Name: "Python", Search: "python"}},
+		Vacancies:    config.VacanciesConfig{Employers: employers},
+	}
+}
+
+func TestCollectEmployers(t *testing.T) {
+	stats := collectEmployers(employersPostings(), employersConfig(config.EmployersConfig{
+		Blacklist: []string{"кадровое агентство"},
+	}))
+
+	require.Len(t, stats, 3, "у Python нет вакансий")
+
+	assert.Equal(t, EmployerStatistics{
+		Technology: "Golang",
+		City:       "MOSCOW",
+		CityCode:   1,
+		Vacancies:  5,
+		Employers: []EmployerStat{
+			{
+				ID: "1740", Name: "Яндекс", Vacancies: 4, Share: 80,
+				Salary: &SalaryRange{Currency: "RUR", From: 150000, To: 400000, Vacancies: 3},
+			},
+			{ID: "84585", Name: "Авито", Vacancies: 1, Share: 20},
+		},
+	}, stats[0])
+
+	assert.Equal(t, "KRASNODAR", stats[1].City)
+	assert.Equal(t, 100.0, stats[1].Employers[0].Share)
+
+	all := stats[2]
+	assert.Zero(t, all.CityCode)
+	assert.Equal(t, 6, all.Vacancies)
+	assert.Equal(t, 83.33, all.Employers[0].Share)
+	assert.Equal(t, &SalaryRange{Currency: "RUR", From: 100000, To: 400000, Vacancies: 4}, all.Employers[0].Salary)
+}
+
+func TestCollectEmployers_WhitelistAndTop(t *testing.T) {
+	stats := collectEmployers(employersPostings(), employersConfig(config.EmployersConfig{
+		Whitelist: []string{"84585", "999"},
+		Top:       1,
+	}))
+
+	require.Len(t, stats, 2, "в Краснодаре только Яндекс, его нет в белом списке")
+	assert.Equal(t, 3, stats[0].Vacancies)
+	assert.Equal(t, []EmployerStat{{
+		ID: "999", Name: "Кадровое агентство", Vacancies: 2, Share: 66.67,
+		Salary: &SalaryRange{Currency: "RUR", From: 500000, To: 900000, Vacancies: 1},
+	}}, stats[0].Employers)
+}
+
+func TestWriteEmployersTXT(t *testing.T) {
+	stats := Statistics{Employers: collectEmployers(employersPostings(), employersConfig(config.EmployersConfig{}))}
+
+	var out bytes.Buffer
+	writeEmployersTXT(&out, stats)
+
+	assert.Contains(t, out.String(), "КРУПНЕЙШИЕ РАБОТОДАТЕЛИ")
+	assert.Contains(t, out.String(), "Golang\tMOSCOW\t7\tЯндекс\t4\t57.14")
+	assert.NotContains(t, out.String(), "Golang\t\t", "строка по всем городам не выводится")
+}
@@ -0,0 +1,156 @@
+package storage
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+const dateLayout = "2006-01-02"
+
+var ErrRunNotFound = errors.New("storage: нет сохранённых данных за эту дату")
+
+// Point — значение серии за одну дату
+type Point struct {
+	Date  string `json:"date"`
+	Count int    `json:"count"`
+}
+
+// ListRuns возвращает даты сохранённых запусков (файлы YYYY-MM-DD.json) по возрастанию
+func ListRuns(directory string) ([]time.Time, error) {
+	entries, err := os.ReadDir(directory)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read data directory: %w", err)
+	}
+
+	var dates []time.Time
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, ".json") {
+			continue
+		}
+
+		date, err := time.Parse(dateLayout, strings.TrimSuffix(name, ".json"))
+		if err != nil {
+			continue
+		}
+		dates = append(dates, date)
+	}
+
+	sort.Slice(dates, func(i, j int) bool { return dates[i].Before(dates[j]) })
+
+	return dates, nil
+}
+
+// LoadStatistics читает сохранённую статистику за дату
+func LoadStatistics(directory string, date time.Time) (Statistics, error) {
+	var stats Statistics
+
+	data, err := os.ReadFile(filepath.Join(directory, date.Format(dateLayout)+".json"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return stats, fmt.Errorf("%w: %s", ErrRunNotFound, date.Format(dateLayout))
+		}
+		return stats, err
+	}
+
+	if err := json.Unmarshal(data, &stats); err != nil {
+		return stats, fmt.Errorf("failed to parse %s: %w", date.Format(dateLayout), err)
+	}
+
+	return stats, nil
+}
+
+// LoadLatest читает статистику последнего запуска
+func LoadLatest(directory string) (Statistics, error) {
+	dates, err := ListRuns(directory)
+	if err != nil {
+		return Statistics{}, err
+	}
+	if len(dates) == 0 {
+		return Statistics{}, ErrRunNotFound
+	}
+
+	return LoadStatistics(directory, dates[len(dates)-1])
+}
+
+// LoadBefore читает последний запуск, сделанный раньше дня date
+func LoadBefore(directory string, date time.Time) (Statistics, error) {
+	dates, err := ListRuns(directory)
+	if err != nil {
+		return Statistics{}, err
+	}
+
+	day := date.Format(dateLayout)
+	for i := len(dates) - 1; i >= 0; i-- {
+		if dates[i].Format(dateLayout) < day {
+			return LoadStatistics(directory, dates[i])
+		}
+	}
+
+	return Statistics{}, ErrRunNotFound
+}
+
+// LoadHistory читает все запуски в диапазоне [from, to]; нулевые границы не ограничивают выборку
+func LoadHistory(directory string, from, to time.Time) ([]Statistics, error) {
+	dates, err := ListRuns(directory)
+	if err != nil {
+		return nil, err
+	}
+
+	var history []Statistics
+	for _, date := range dates {
+		if (!from.IsZero() && date.Before(from)) || (!to.IsZero() && date.After(to)) {
+			continue
+		}
+
+		stats, err := LoadStatistics(directory, date)
+		if err != nil {
+			return nil, err
+		}
+		history = append(history, stats)
+	}
+
+	return history, nil
+}
+
+// Series строит временной ряд технологии (ключ серии) по городу;
+// пустой city означает сумму по всем городам.
+func Series(history []Statistics, key, city string) []Point {
+	points := make([]Point, 0, len(history))
+
+	for _, stats := range history {
+		count, ok := stats.Count(key, city)
+		if !ok {
+			continue
+		}
+		points = append(points, Point{Date: stats.Date.Format(dateLayout), Count: count})
+	}
+
+	return points
+}
+
+// Count возвращает число вакансий технологии в городе или, если city пустой, по всем городам
+func (s Statistics) Count(key, city string) (int, bool) {
+	if city == "" {
+		count, ok := s.Summary[key]
+		return count, ok
+	}
+
+	for _, c := range s.Cities {
+		if c.Name == city {
+			count, ok := c.Vacancies[key]
+			return count, ok
+		}
+	}
+
+	return 0, false
+}
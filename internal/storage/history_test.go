@@ -0,0 +1,88 @@
+package storage
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func historyStats(day int, moscow, krasnodar int) Statistics {
+	return Statistics{
+		Date: time.Date(2026, 2, day, 12, 0, 0, 0, time.UTC),
+		Cities: []CityStatistics{
+			{Name: "MOSCOW", Vacancies: map[string]int{"Golang": moscow}, Total: moscow},
+			{Name: "KRASNODAR", Vacancies: map[string]int{"Golang": krasnodar}, Total: krasnodar},
+		},
+		Summary: map[string]int{"Golang": moscow + krasnodar},
+		Total:   moscow + krasnodar,
+	}
+}
+
+func TestHistory_ListLoadSeries(t *testing.T) {
+	tempDir := t.TempDir()
+
+	require.NoError(t, saveJSON(historyStats(15, 420, 6), tempDir))
+	require.NoError(t, saveJSON(historyStats(14, 400, 5), tempDir))
+	require.NoError(t, saveJSON(historyStats(16, 380, 8), tempDir))
+	// Посторонние файлы игнорируются
+	require.NoError(t, os.WriteFile(filepath.Join(tempDir, "areas.json"), []byte("[]"), 0o644))
+	require.NoError(t, saveTXT(historyStats(16, 380, 8), tempDir))
+
+	dates, err := ListRuns(tempDir)
+	require.NoError(t, err)
+	require.Len(t, dates, 3)
+	assert.Equal(t, "2026-02-14", dates[0].Format(dateLayout))
+	assert.Equal(t, "2026-02-16", dates[2].Format(dateLayout))
+
+	latest, err := LoadLatest(tempDir)
+	require.NoError(t, err)
+	assert.Equal(t, 388, latest.Total)
+
+	history, err := LoadHistory(tempDir, time.Date(2026, 2, 15, 0, 0, 0, 0, time.UTC), time.Time{})
+	require.NoError(t, err)
+	require.Len(t, history, 2)
+
+	assert.Equal(t, []Point{{"2026-02-15", 426}, {"2026-02-16", 388}}, Series(history, "Golang", ""))
+	assert.Equal(t, []Point{{"2026-02-15", 6}, {"2026-02-16", 8}}, Series(history, "Golang", "KRASNODAR"))
+	assert.Empty(t, Series(history, "Python", ""))
+}
+
+func TestHistory_NotFound(t *testing.T) {
+	tempDir := t.TempDir()
+
+	_, err := LoadLatest(tempDir)
+	assert.ErrorIs(t, err, ErrRunNotFound)
+
+	_, err = LoadStatistics(tempDir, time.Date(2026, 2, 14, 0, 0, 0, 0, time.UTC))
+	assert.ErrorIs(t, err, ErrRunNotFound)
+
+	dates, err := ListRuns(filepath.Join(tempDir, "missing"))
+	assert.NoError(t, err)
+	assert.Empty(t, dates)
+}
+
+func TestCompare(t *testing.T) {
+	from := historyStats(14, 400, 5)
+	to := historyStats(15, 420, 0)
+	to.Summary["Python"] = 10
+	to.Total += 10
+
+	diff := Compare(from, to)
+
+	assert.Equal(t, "2026-02-14", diff.From)
+	assert.Equal(t, "2026-02-15", diff.To)
+	assert.Equal(t, Change{From: 405, To: 430, Delta: 25, Percent: 6.17}, diff.Total)
+
+	require.Len(t, diff.Technologies, 2)
+	golang := diff.Technologies[0]
+	assert.Equal(t, "Golang", golang.Technology)
+	assert.Equal(t, 15, golang.Delta)
+	assert.Equal(t, Change{From: 5, To: 0, Delta: -5, Percent: -100}, golang.Cities["KRASNODAR"])
+
+	python := diff.Technologies[1]
+	assert.Equal(t, Change{From: 0, To: 10, Delta: 10}, python.Change)
+}
@@ -0,0 +1,459 @@
+package storage
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hhparser/internal/config"
+	"hhparser/internal/hhparser"
+	"io"
+	"log/slog"
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+// registryFile — реестр вакансий в PostingsDir
+const registryFile = "registry.json"
+
+// Registry — все вакансии, встреченные запусками со сбором вакансий. Обновляется
+// каждым таким запуском, поэтому история не пересчитывается по снимкам.
+type Registry struct {
+	UpdatedAt time.Time                 `json:"updatedAt"`
+	Postings  map[string]*PostingRecord `json:"vacancies"`
+
+	// Начало последнего сбора, после которого реестр полон: с него продолжает
+	// инкрементальный сбор. FullSync — то же для последнего полного обхода выдачи.
+	Checkpoint time.Time `json:"checkpoint"`
+	FullSync   time.Time `json:"fullSync"`
+}
+
+// PostingRecord — история одной вакансии между запусками
+type PostingRecord struct {
+	hhparser.Posting // последняя увиденная версия
+
+	OpenedAt  time.Time  `json:"openedAt"` // дата публикации при первой встрече, если известна, иначе firstSeen
+	FirstSeen time.Time  `json:"firstSeen"`
+	LastSeen  time.Time  `json:"lastSeen"`
+	ClosedAt  *time.Time `json:"closedAt,omitempty"` // первый запуск, в котором вакансии уже не было
+	RepostOf  string     `json:"repostOf,omitempty"` // id прежней вакансии с тем же работодателем, названием и регионом
+}
+
+// LifecycleStatistics — движение вакансий за запуск по реестру
+type LifecycleStatistics struct {
+	Active       int                   `json:"active"`
+	New          int                   `json:"new"`
+	Closed       int                   `json:"closed"`
+	Reposted     int                   `json:"reposted"`
+	Technologies []TechnologyLifecycle `json:"technologies"`
+
+	// Инкрементальный запуск не видит снятых вакансий: Closed равен нулю, а Active
+	// и статистика по реестру могут включать уже закрытые до следующего полного обхода
+	Approximate bool `json:"approximate,omitempty"`
+}
+
+// TechnologyLifecycle — движение вакансий технологии в городе.
+// City пустой и CityCode 0 — по всем городам вместе.
+type TechnologyLifecycle struct {
+	Technology string `json:"technology"`
+	City       string `json:"city,omitempty"`
+	CityCode   int    `json:"cityCode,omitempty"`
+	Active     int    `json:"active"`
+	New        int    `json:"new"`
+	Closed     int    `json:"closed"`
+	Reposted   int    `json:"reposted"`
+	// Среднее число дней от публикации до закрытия по всем закрытым вакансиям реестра
+	AvgDaysOpen float64 `json:"avgDaysOpen"`
+	ClosedTotal int     `json:"closedTotal"`
+}
+
+// LoadRegistry читает реестр вакансий; если его ещё нет, возвращает пустой
+func LoadRegistry(directory string) (*Registry, error) {
+	registry := &Registry{Postings: make(map[string]*PostingRecord)}
+
+	data, err := os.ReadFile(filepath.Join(directory, PostingsDir, registryFile))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return registry, nil
+		}
+		return nil, err
+	}
+
+	if err := json.Unmarshal(data, registry); err != nil {
+		return nil, fmt.Errorf("failed to parse vacancies registry: %w", err)
+	}
+	if registry.Postings == nil {
+		registry.Postings = make(map[string]*PostingRecord)
+	}
+	return registry, nil
+}
+
+// Known возвращает последние версии вакансий реестра по id — для hhparser.ParserConfig.Known
+func (r *Registry) Known() map[string]*hhparser.Posting {
+	known := make(map[string]*hhparser.Posting, len(r.Postings))
+	for id, record := range r.Postings {
+		known[id] = &record.Posting
+	}
+	return known
+}
+
+// Since возвращает, с какого момента запрашивать вакансии в инкрементальном режиме.
+// Нулевое время — нужен полный обход: режим выключен, полного обхода ещё не было
+// или с него прошло full_resync_days.
+func (r *Registry) Since(cfg config.IncrementalConfig, now time.Time) time.Time {
+	if !cfg.Enabled || r.Checkpoint.IsZero() || r.FullSync.IsZero() {
+		return time.Time{}
+	}
+	if now.Sub(r.FullSync) >= time.Duration(cfg.FullResyncDays)*24*time.Hour {
+		return time.Time{}
+	}
+	return r.Checkpoint
+}
+
+// advance сдвигает контрольную точку на начало сбора, если ничего не пропущено.
+// Упор полного обхода в max_pages не мешает: следующий полный обход упрётся так же.
+// Инкрементальный сбор должен получить всю выдачу с прошлой точки.
+func (r *Registry) advance(report *hhparser.PostingsReport, startedAt time.Time) {
+	incremental := report != nil && report.Since != nil
+	if report != nil {
+		for _, query := range report.Incomplete {
+			if !query.Truncated || incremental {
+				slog.Warn("vacancies checkpoint not advanced",
+					"technology", query.Technology, "city", query.City, "truncated", query.Truncated)
+				return
+			}
+		}
+	}
+
+	r.Checkpoint = startedAt
+	if !incremental {
+		r.FullSync = startedAt
+	}
+}
+
+// active возвращает активные вакансии реестра по настроенным технологиям и городам
+// в порядке id — набор вакансий инкрементального запуска
+func (r *Registry) active(cfg StorageConfig) []*hhparser.Posting {
+	technologies := make(map[string]bool, len(cfg.Technologies))
+	for _, tech := range cfg.Technologies {
+		technologies[tech.Key()] = true
+	}
+	cities := make(map[int]bool, len(cfg.Cities))
+	for _, city := range cfg.Cities {
+		cities[city.Code] = true
+	}
+
+	postings := make([]*hhparser.Posting, 0, len(r.Postings))
+	for _, record := range r.Postings {
+		if record.ClosedAt != nil || !cities[record.City] {
+			continue
+		}
+		posting := record.Posting
+		posting.Technologies = slices.DeleteFunc(slices.Clone(posting.Technologies),
+			func(technology string) bool { return !technologies[technology] })
+		if len(posting.Technologies) == 0 {
+			continue
+		}
+		postings = append(postings, &posting)
+	}
+	slices.SortFunc(postings, func(a, b *hhparser.Posting) int { return strings.Compare(a.ID, b.ID) })
+	return postings
+}
+
+func (r *Registry) save(directory string) error {
+	dir := filepath.Join(directory, PostingsDir)
+	if err := ensureDir(dir); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	// Реестр копится между запусками: пишем во временный файл и переименовываем,
+	// чтобы сбой посреди записи не стёр историю
+	tmp, err := os.CreateTemp(dir, "registry-*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), filepath.Join(dir, registryFile))
+}
+
+// lifecycleChanges — что изменилось в реестре за запуск
+type lifecycleChanges struct {
+	added    []*PostingRecord
+	closed   []*PostingRecord
+	reposted []*PostingRecord
+}
+
+// update отмечает вакансии запуска. Вакансия, которой больше нет в выдаче, закрывается,
+// только если все её запросы выполнены в этом запуске полностью: пропуск из-за ошибки
+// или max_pages закрытием не считается.
+func (r *Registry) update(postings []*hhparser.Posting, covered func(*PostingRecord) bool, date time.Time, cfg config.LifecycleConfig) lifecycleChanges {
+	var changes lifecycleChanges
+	window := time.Duration(cfg.RepostWindowDays) * 24 * time.Hour
+
+	seen := make(map[string]bool, len(postings))
+	for _, posting := range postings {
+		seen[posting.ID] = true
+
+		record := r.Postings[posting.ID]
+		if record != nil {
+			record.Posting = *posting
+			record.LastSeen = date
+			record.ClosedAt = nil
+			continue
+		}
+
+		record = &PostingRecord{
+			Posting:   *posting,
+			OpenedAt:  posting.PublishedAt,
+			FirstSeen: date,
+			LastSeen:  date,
+		}
+		if record.OpenedAt.IsZero() || record.OpenedAt.After(date) {
+			record.OpenedAt = date
+		}
+		r.Postings[posting.ID] = record
+		changes.added = append(changes.added, record)
+	}
+
+	for id, record := range r.Postings {
+		if seen[id] {
+			continue
+		}
+		if record.ClosedAt == nil {
+			if covered(record) {
+				closedAt := date
+				record.ClosedAt = &closedAt
+				changes.closed = append(changes.closed, record)
+			}
+			continue
+		}
+		if cfg.RetentionDays > 0 && date.Sub(*record.ClosedAt) > time.Duration(cfg.RetentionDays)*24*time.Hour {
+			delete(r.Postings, id)
+		}
+	}
+
+	// Перепубликация — новая вакансия вместо закрытой в пределах окна, в том числе
+	// закрытой в этом же запуске. Такая же, но по-прежнему открытая вакансия ею не считается.
+	bySignature := make(map[string]*PostingRecord)
+	for _, record := range r.Postings {
+		if record.ClosedAt == nil || date.Sub(*record.ClosedAt) > window {
+			continue
+		}
+		signature := postingSignature(record.Posting)
+		if signature == "" {
+			continue
+		}
+		if previous := bySignature[signature]; previous == nil || record.LastSeen.After(previous.LastSeen) {
+			bySignature[signature] = record
+		}
+	}
+	for _, record := range changes.added {
+		if previous := bySignature[postingSignature(record.Posting)]; previous != nil {
+			record.RepostOf = previous.ID
+			changes.reposted = append(changes.reposted, record)
+		}
+	}
+
+	r.UpdatedAt = date
+	return changes
+}
+
+// postingSignature — работодатель, название и регион; пусто, если работодатель неизвестен
+func postingSignature(posting hhparser.Posting) string {
+	employer := posting.Employer.ID
+	if employer == "" {
+		employer = posting.Employer.Name
+	}
+	if employer == "" {
+		return ""
+	}
+	return strings.ToLower(strings.Join([]string{employer, strings.TrimSpace(posting.Name), posting.Area.ID}, "|"))
+}
+
+// updateLifecycle обновляет реестр вакансиями запуска и считает движение вакансий.
+// Возвращает вакансии, по которым считается статистика: после инкрементального
+// сбора — все активные вакансии реестра, иначе — вакансии запуска.
+func updateLifecycle(cfg StorageConfig, date time.Time) (*LifecycleStatistics, []*hhparser.Posting, error) {
+	registry, err := LoadRegistry(cfg.DataDir)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var report *hhparser.PostingsReport
+	startedAt := date
+	if cfg.Run != nil {
+		report = cfg.Run.Postings
+		if !cfg.Run.StartedAt.IsZero() {
+			startedAt = cfg.Run.StartedAt
+		}
+	}
+	incremental := report != nil && report.Since != nil
+
+	// Инкрементальный сбор видит только новые и обновлённые вакансии:
+	// отсутствие в выдаче ничего не говорит о закрытии
+	covered := func(*PostingRecord) bool { return false }
+	if !incremental {
+		var incomplete []hhparser.PostingsQuery
+		if report != nil {
+			incomplete = report.Incomplete
+		}
+		covered = queryCoverage(cfg, incomplete)
+	}
+
+	changes := registry.update(cfg.Postings, covered, date, cfg.Vacancies.Lifecycle)
+	registry.advance(report, startedAt)
+
+	if err := registry.save(cfg.DataDir); err != nil {
+		return nil, nil, err
+	}
+
+	postings := cfg.Postings
+	if incremental {
+		postings = registry.active(cfg)
+	}
+	lifecycle := collectLifecycle(registry, changes, cfg)
+	lifecycle.Approximate = incremental
+	return lifecycle, postings, nil
+}
+
+// queryCoverage сообщает, выполнены ли в запуске полностью все запросы вакансии
+func queryCoverage(cfg StorageConfig, incomplete []hhparser.PostingsQuery) func(*PostingRecord) bool {
+	type key struct {
+		technology string
+		city       int
+	}
+	skipped := make(map[key]bool, len(incomplete))
+	for _, query := range incomplete {
+		skipped[key{query.Technology, query.City}] = true
+	}
+	technologies := make(map[string]bool, len(cfg.Technologies))
+	for _, tech := range cfg.Technologies {
+		technologies[tech.Key()] = true
+	}
+	cities := make(map[int]bool, len(cfg.Cities))
+	for _, city := range cfg.Cities {
+		cities[city.Code] = true
+	}
+
+	return func(record *PostingRecord) bool {
+		if !cities[record.City] || len(record.Technologies) == 0 {
+			return false
+		}
+		for _, technology := range record.Technologies {
+			if !technologies[technology] || skipped[key{technology, record.City}] {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+func collectLifecycle(registry *Registry, changes lifecycleChanges, cfg StorageConfig) *LifecycleStatistics {
+	cityNames := make(map[int]string, len(cfg.Cities))
+	for _, city := range cfg.Cities {
+		cityNames[city.Code] = city.Name
+	}
+
+	type key struct {
+		technology string
+		city       int // 0 — все города
+	}
+	type counters struct {
+		TechnologyLifecycle
+		daysOpen float64
+	}
+	byKey := make(map[key]*counters)
+	each := func(record *PostingRecord, fn func(c *counters)) {
+		for _, technology := range record.Technologies {
+			for _, city := range []int{record.City, 0} {
+				k := key{technology, city}
+				if byKey[k] == nil {
+					byKey[k] = &counters{}
+				}
+				fn(byKey[k])
+			}
+		}
+	}
+
+	lifecycle := &LifecycleStatistics{
+		New:      len(changes.added),
+		Closed:   len(changes.closed),
+		Reposted: len(changes.reposted),
+	}
+
+	for _, record := range registry.Postings {
+		if record.ClosedAt == nil {
+			lifecycle.Active++
+			each(record, func(c *counters) { c.Active++ })
+			continue
+		}
+		days := record.ClosedAt.Sub(record.OpenedAt).Hours() / 24
+		each(record, func(c *counters) {
+			c.ClosedTotal++
+			c.daysOpen += days
+		})
+	}
+	for _, record := range changes.added {
+		each(record, func(c *counters) { c.New++ })
+	}
+	for _, record := range changes.closed {
+		each(record, func(c *counters) { c.Closed++ })
+	}
+	for _, record := range changes.reposted {
+		each(record, func(c *counters) { c.Reposted++ })
+	}
+
+	for _, tech := range cfg.Technologies {
+		for _, city := range append(cityCodes(cfg.Cities), 0) {
+			c := byKey[key{tech.Key(), city}]
+			if c == nil {
+				continue
+			}
+			stat := c.TechnologyLifecycle
+			stat.Technology, stat.City, stat.CityCode = tech.Key(), cityNames[city], city
+			if stat.ClosedTotal > 0 {
+				stat.AvgDaysOpen = math.Round(c.daysOpen/float64(stat.ClosedTotal)*10) / 10
+			}
+			lifecycle.Technologies = append(lifecycle.Technologies, stat)
+		}
+	}
+
+	return lifecycle
+}
+
+func writeLifecycleTXT(w io.Writer, stats Statistics) {
+	lifecycle := stats.Lifecycle
+	if lifecycle == nil {
+		return
+	}
+
+	fmt.Fprintf(w, "\nДВИЖЕНИЕ ВАКАНСИЙ (активных %d, новых %d, закрыто %d, перепубликаций %d)\n",
+		lifecycle.Active, lifecycle.New, lifecycle.Closed, lifecycle.Reposted)
+	if lifecycle.Approximate {
+		fmt.Fprintln(w, "Приблизительно: инкрементальный запуск, закрытые вакансии отмечаются при полном обходе")
+	}
+	fmt.Fprintln(w, "Технология\tГород\tАктивных\tНовых\tЗакрыто\tПерепубликаций\tДней открыта")
+	for _, stat := range lifecycle.Technologies {
+		if stat.CityCode == 0 {
+			continue
+		}
+		fmt.Fprintf(w, "%s\t%s\t%d\t%d\t%d\t%d\t%.1f\n",
+			stat.Technology, stat.City, stat.Active, stat.New, stat.Closed, stat.Reposted, stat.AvgDaysOpen)
+	}
+}
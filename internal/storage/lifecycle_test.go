@@ -0,0 +1,234 @@
+package storage
+
+import (
+	"bytes"
+	"hhparser/internal/config"
+	"hhparser/internal/hhparser"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func lifecycleConfig(dir string) StorageConfig {
+	return StorageConfig{
+		Cities:       []config.CityConfig{{Name: "MOSCOW", Code: 1}, {Name: "KRASNODAR", Code: 53}},
+		Technologies: []config.TechnologyConfig{{Name: "Golang", Search: "golang"}, {Name: "Python", Search: "python"}},
+		DataDir:      dir,
+		Vacancies: config.VacanciesConfig{
+			Lifecycle: config.LifecycleConfig{RepostWindowDays: 30, RetentionDays: 180},
+		},
+	}
+}
+
+func lifecyclePosting(id, name string, city int, published time.Time, technologies ...string) *hhparser.Posting {
+	return &hhparser.Posting{
+		ID:           id,
+		Name:         name,
+		Employer:     hhparser.Employer{ID: "1740", Name: "Яндекс"},
+		Area:         hhparser.Area{ID: "1"},
+		PublishedAt:  published,
+		City:         city,
+		Technologies: technologies,
+	}
+}
+
+func day(n int) time.Time {
+	return time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC).AddDate(0, 0, n)
+}
+
+func TestUpdateLifecycle(t *testing.T) {
+	cfg := lifecycleConfig(t.TempDir())
+
+	cfg.Postings = []*hhparser.Posting{
+		lifecyclePosting("1", "Go developer", 1, day(-4), "Golang"),
+		lifecyclePosting("2", "Python developer", 1, day(-1), "Python"),
+		lifecyclePosting("3", "Backend", 53, time.Time{}, "Golang", "Python"),
+	}
+	first, _, err := updateLifecycle(cfg, day(0))
+	require.NoError(t, err)
+	assert.Equal(t, 3, first.Active)
+	assert.Equal(t, 3, first.New)
+	assert.Zero(t, first.Closed)
+
+	// Через 10 дней вакансия 1 пропала, а её перепубликовали под новым id;
+	// вакансия 3 тоже пропала, но выдача Python в Краснодаре обойдена не полностью
+	cfg.Postings = []*hhparser.Posting{
+		lifecyclePosting("2", "Python developer", 1, day(-1), "Python"),
+		lifecyclePosting("4", "Go Developer ", 1, day(9), "Golang"),
+	}
+	cfg.Run = &RunInfo{Postings: &hhparser.PostingsReport{
+		Incomplete: []hhparser.PostingsQuery{{Technology: "Python", City: 53}},
+	}}
+	second, _, err := updateLifecycle(cfg, day(10))
+	require.NoError(t, err)
+	assert.Equal(t, 3, second.Active, "вакансия 3 не закрыта: её запрос не завершён")
+	assert.Equal(t, 1, second.New)
+	assert.Equal(t, 1, second.Closed)
+	assert.Equal(t, 1, second.Reposted)
+
+	registry, err := LoadRegistry(cfg.DataDir)
+	require.NoError(t, err)
+	require.Len(t, registry.Postings, 4)
+	assert.Equal(t, "1", registry.Postings["4"].RepostOf)
+	assert.Equal(t, day(10), *registry.Postings["1"].ClosedAt)
+	assert.Equal(t, day(0), registry.Postings["3"].OpenedAt, "без даты публикации — дата первой встречи")
+	assert.Equal(t, day(10), registry.Postings["2"].LastSeen)
+
+	assert.Equal(t, TechnologyLifecycle{
+		Technology: "Golang", City: "MOSCOW", CityCode: 1,
+		Active: 1, New: 1, Closed: 1, Reposted: 1,
+		AvgDaysOpen: 14, ClosedTotal: 1,
+	}, second.Technologies[0])
+	assert.Equal(t, "KRASNODAR", second.Technologies[1].City)
+	assert.Zero(t, second.Technologies[2].CityCode)
+	assert.Equal(t, 2, second.Technologies[2].Active)
+
+	// Вакансия вернулась в выдачу — снова активна; закрытые дольше retention_days удаляются
+	cfg.Run = nil
+	cfg.Postings = []*hhparser.Posting{lifecyclePosting("1", "Go developer", 1, day(-4), "Golang")}
+	_, _, err = updateLifecycle(cfg, day(20))
+	require.NoError(t, err)
+
+	registry, err = LoadRegistry(cfg.DataDir)
+	require.NoError(t, err)
+	assert.Nil(t, registry.Postings["1"].ClosedAt)
+	assert.NotNil(t, registry.Postings["3"].ClosedAt)
+
+	cfg.Postings = []*hhparser.Posting{}
+	_, _, err = updateLifecycle(cfg, day(220))
+	require.NoError(t, err)
+	_, _, err = updateLifecycle(cfg, day(410))
+	require.NoError(t, err)
+
+	registry, err = LoadRegistry(cfg.DataDir)
+	require.NoError(t, err)
+	assert.Empty(t, registry.Postings)
+}
+
+func TestRegistryUpdate_RepostWindow(t *testing.T) {
+	registry := &Registry{Postings: make(map[string]*PostingRecord)}
+	lifecycle := config.LifecycleConfig{RepostWindowDays: 30}
+	all := func(*PostingRecord) bool { return true }
+
+	registry.update([]*hhparser.Posting{lifecyclePosting("1", "Go developer", 1, day(0), "Golang")}, all, day(0), lifecycle)
+	registry.update(nil, all, day(1), lifecycle)
+
+	changes := registry.update([]*hhparser.Posting{lifecyclePosting("2", "Go developer", 1, day(40), "Golang")}, all, day(40), lifecycle)
+	assert.Len(t, changes.added, 1)
+	assert.Empty(t, changes.reposted, "прежняя вакансия закрыта больше 30 дней назад")
+	assert.Empty(t, registry.Postings["2"].RepostOf)
+}
+
+func TestRegistryUpdate_OpenTwinNotRepost(t *testing.T) {
+	registry := &Registry{Postings: make(map[string]*PostingRecord)}
+	lifecycle := config.LifecycleConfig{RepostWindowDays: 30}
+	all := func(*PostingRecord) bool { return true }
+
+	first := lifecyclePosting("1", "Go developer", 1, day(0), "Golang")
+	registry.update([]*hhparser.Posting{first}, all, day(0), lifecycle)
+
+	// Работодатель открыл вторую такую же вакансию, первая по-прежнему в выдаче
+	changes := registry.update([]*hhparser.Posting{first, lifecyclePosting("2", "Go developer", 1, day(1), "Golang")}, all, day(1), lifecycle)
+	assert.Len(t, changes.added, 1)
+	assert.Empty(t, changes.reposted, "открытая вакансия не перепубликована")
+	assert.Empty(t, registry.Postings["2"].RepostOf)
+
+	// Инкрементальный запуск не видит первую, но и не закрывает её
+	none := func(*PostingRecord) bool { return false }
+	changes = registry.update([]*hhparser.Posting{lifecyclePosting("3", "Go developer", 1, day(2), "Golang")}, none, day(2), lifecycle)
+	assert.Empty(t, changes.reposted, "незакрытая вакансия не перепубликована")
+}
+
+func TestKnown(t *testing.T) {
+	registry := &Registry{Postings: map[string]*PostingRecord{
+		"1": {Posting: hhparser.Posting{ID: "1", KeySkills: []string{"Go"}, Detailed: true}},
+	}}
+	assert.Equal(t, []string{"Go"}, registry.Known()["1"].KeySkills)
+
+	empty, err := LoadRegistry(t.TempDir())
+	require.NoError(t, err)
+	assert.Empty(t, empty.Known())
+}
+
+func TestWriteLifecycleTXT(t *testing.T) {
+	stats := Statistics{Lifecycle: &LifecycleStatistics{
+		Active: 3, New: 1, Closed: 1, Reposted: 1,
+		Technologies: []TechnologyLifecycle{
+			{Technology: "Golang", City: "MOSCOW", CityCode: 1, Active: 1, New: 1, Closed: 1, Reposted: 1, AvgDaysOpen: 14},
+			{Technology: "Golang", Active: 2},
+		},
+	}}
+
+	var out bytes.Buffer
+	writeLifecycleTXT(&out, stats)
+
+	assert.Contains(t, out.String(), "ДВИЖЕНИЕ ВАКАНСИЙ (активных 3, новых 1, закрыто 1, перепубликаций 1)")
+	assert.Contains(t, out.String(), "Golang\tMOSCOW\t1\t1\t1\t1\t14.0\n")
+	assert.NotContains(t, out.String(), "Golang\t\t")
+}
+
+func TestUpdateLifecycle_Incremental(t *testing.T) {
+	cfg := lifecycleConfig(t.TempDir())
+	cfg.Postings = []*hhparser.Posting{
+		lifecyclePosting("1", "Go developer", 1, day(-4), "Golang"),
+		lifecyclePosting("2", "Python developer", 53, day(-1), "Python"),
+	}
+	_, _, err := updateLifecycle(cfg, day(0))
+	require.NoError(t, err)
+
+	// Инкрементальный сбор принёс только новую вакансию: прежние не закрываются
+	// и попадают в набор вакансий запуска из реестра
+	since := day(0)
+	cfg.Run = &RunInfo{StartedAt: day(1), Postings: &hhparser.PostingsReport{Since: &since}}
+	cfg.Postings = []*hhparser.Posting{lifecyclePosting("3", "Backend", 1, day(1), "Golang", "Kafka")}
+
+	lifecycle, postings, err := updateLifecycle(cfg, day(1))
+	require.NoError(t, err)
+	assert.Zero(t, lifecycle.Closed)
+	assert.True(t, lifecycle.Approximate)
+	assert.Equal(t, 1, lifecycle.New)
+	assert.Equal(t, 3, lifecycle.Active)
+
+	require.Len(t, postings, 3)
+	assert.Equal(t, []string{"1", "2", "3"}, []string{postings[0].ID, postings[1].ID, postings[2].ID})
+	assert.Equal(t, []string{"Golang"}, postings[2].Technologies, "ненастроенные технологии отбрасываются")
+
+	registry, err := LoadRegistry(cfg.DataDir)
+	require.NoError(t, err)
+	assert.Equal(t, day(1), registry.Checkpoint)
+	assert.Equal(t, day(0), registry.FullSync, "полный обход был в первом запуске")
+}
+
+func TestRegistry_Checkpoint(t *testing.T) {
+	incremental := config.IncrementalConfig{Enabled: true, FullResyncDays: 7}
+	registry := &Registry{Postings: make(map[string]*PostingRecord)}
+	assert.True(t, registry.Since(incremental, day(0)).IsZero(), "без полного обхода — полный обход")
+
+	registry.advance(&hhparser.PostingsReport{
+		Incomplete: []hhparser.PostingsQuery{{Technology: "Python", City: 1, Truncated: true}},
+	}, day(0))
+	assert.Equal(t, day(0), registry.FullSync, "упор полного обхода в max_pages не мешает")
+	assert.Equal(t, day(0), registry.Since(incremental, day(1)))
+	assert.True(t, registry.Since(config.IncrementalConfig{FullResyncDays: 7}, day(1)).IsZero(), "режим выключен")
+	assert.True(t, registry.Since(incremental, day(7)).IsZero(), "пора полного обхода")
+
+	since := day(0)
+	registry.advance(&hhparser.PostingsReport{Since: &since}, day(1))
+	assert.Equal(t, day(1), registry.Checkpoint)
+	assert.Equal(t, day(0), registry.FullSync)
+
+	since = day(1)
+	registry.advance(&hhparser.PostingsReport{
+		Since:      &since,
+		Incomplete: []hhparser.PostingsQuery{{Technology: "Python", City: 1, Truncated: true}},
+	}, day(2))
+	assert.Equal(t, day(1), registry.Checkpoint, "инкрементальный сбор пропустил вакансии")
+
+	registry.advance(&hhparser.PostingsReport{
+		Incomplete: []hhparser.PostingsQuery{{Technology: "Python", City: 1}},
+	}, day(3))
+	assert.Equal(t, day(1), registry.Checkpoint, "ошибка полного обхода")
+	assert.Equal(t, day(0), registry.FullSync)
+}
@@ -0,0 +1,105 @@
+package storage
+
+import (
+	"bytes"
+	"fmt"
+	"hhparser/internal/config"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+const lineProtocolTimeout = 10 * time.Second
+
+var (
+	measurementEscaper = strings.NewReplacer(`,`, `\,`, ` `, `\ `)
+	tagEscaper         = strings.NewReplacer(`,`, `\,`, ` `, `\ `, `=`, `\=`)
+)
+
+// saveLineProtocol выгружает каждое значение CityStatistics.Vacancies точкой
+// line protocol: теги category/city/technology, поле count, время запуска.
+func saveLineProtocol(stats Statistics, cfg config.LineProtocolConfig, directory string) error {
+	var buf bytes.Buffer
+	writeLineProtocol(&buf, stats, cfg.Measurement)
+
+	if cfg.WriteUrl != "" {
+		return pushLineProtocol(buf.Bytes(), cfg)
+	}
+
+	return os.WriteFile(fmt.Sprintf("%s/lines_%s.lp",
+		directory,
+		stats.Date.Format("2006-01-02")), buf.Bytes(), 0o644)
+}
+
+func writeLineProtocol(w io.Writer, stats Statistics, measurement string) {
+	categories := make(map[string]string, len(stats.Technologies))
+	for _, tech := range stats.Technologies {
+		categories[tech.Key()] = tech.Category
+	}
+
+	timestamp := stats.Date.UnixNano()
+	for _, city := range stats.Cities {
+		keys := make([]string, 0, len(city.Vacancies))
+		for key := range city.Vacancies {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			tags := map[string]string{
+				"city":       city.Name,
+				"category":   categories[key],
+				"technology": key,
+			}
+			fmt.Fprintf(w, "%s%s count=%di %d\n", measurementEscaper.Replace(measurement), formatTags(tags), city.Vacancies[key], timestamp)
+		}
+	}
+}
+
+// formatTags — теги в порядке ключей, как рекомендует InfluxDB: так быстрее запись
+// и ключ серии не зависит от порядка. Пустые значения тегов line protocol не допускает.
+func formatTags(tags map[string]string) string {
+	names := make([]string, 0, len(tags))
+	for name, value := range tags {
+		if value != "" {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	for _, name := range names {
+		fmt.Fprintf(&b, ",%s=%s", name, tagEscaper.Replace(tags[name]))
+	}
+	return b.String()
+}
+
+func pushLineProtocol(body []byte, cfg config.LineProtocolConfig) error {
+	req, err := http.NewRequest(http.MethodPost, cfg.WriteUrl, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create line protocol request: %w", err)
+	}
+	req.Header.Set("Content-Type", "text/plain; charset=utf-8")
+	if cfg.Token != "" {
+		req.Header.Set("Authorization", "Token "+cfg.Token)
+	}
+
+	client := &http.Client{Timeout: lineProtocolTimeout}
+	res, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to push line protocol: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		message, _ := io.ReadAll(io.LimitReader(res.Body, 512))
+		return fmt.Errorf("failed to push line protocol: status %d: %s", res.StatusCode, strings.TrimSpace(string(message)))
+	}
+
+	slog.Debug("line protocol pushed", "url", cfg.WriteUrl, "status", res.StatusCode, "bytes", len(body))
+	return nil
+}
@@ -0,0 +1,108 @@
+package storage
+
+import (
+	"hhparser/internal/config"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func lineProtocolStats() Statistics {
+	return Statistics{
+		Date: time.Date(2026, 2, 14, 0, 0, 0, 0, time.UTC),
+		Technologies: []config.TechnologyConfig{
+			{Name: "Golang", Category: "languages"},
+			{Name: "Team lead"},
+			{Name: "Golang", Category: "languages", Filters: &config.FilterConfig{Schedule: "remote"}},
+		},
+		Cities: []CityStatistics{
+			{
+				Name: "MOSCOW",
+				Vacancies: map[string]int{
+					"Golang":                  306,
+					"Team lead":               50,
+					"Golang[schedule=remote]": 120,
+				},
+			},
+			{
+				Name:      "NIZHNY NOVGOROD",
+				Vacancies: map[string]int{"Golang": 4},
+			},
+		},
+	}
+}
+
+func TestWriteLineProtocol(t *testing.T) {
+	var out strings.Builder
+	writeLineProtocol(&out, lineProtocolStats(), "hh_vacancies")
+
+	expected := `hh_vacancies,category=languages,city=MOSCOW,technology=Golang count=306i 1771027200000000000
+hh_vacancies,category=languages,city=MOSCOW,technology=Golang[schedule\=remote] count=120i 1771027200000000000
+hh_vacancies,city=MOSCOW,technology=Team\ lead count=50i 1771027200000000000
+hh_vacancies,category=languages,city=NIZHNY\ NOVGOROD,technology=Golang count=4i 1771027200000000000
+`
+	assert.Equal(t, expected, out.String())
+}
+
+func TestSaveLineProtocol_File(t *testing.T) {
+	tempDir := t.TempDir()
+
+	err := saveLineProtocol(lineProtocolStats(), config.LineProtocolConfig{Enabled: true, Measurement: "hh"}, tempDir)
+	require.NoError(t, err)
+
+	data, err := os.ReadFile(filepath.Join(tempDir, "lines_2026-02-14.lp"))
+	require.NoError(t, err)
+	assert.Equal(t, 4, strings.Count(string(data), "\n"))
+	assert.True(t, strings.HasPrefix(string(data), "hh,category=languages,city=MOSCOW"))
+}
+
+func TestSaveLineProtocol_Push(t *testing.T) {
+	var (
+		body          string
+		authorization string
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		body = string(data)
+		authorization = r.Header.Get("Authorization")
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	tempDir := t.TempDir()
+	cfg := config.LineProtocolConfig{
+		Enabled:     true,
+		Measurement: "hh_vacancies",
+		WriteUrl:    server.URL + "/api/v2/write?bucket=hh",
+		Token:       "secret",
+	}
+
+	require.NoError(t, saveLineProtocol(lineProtocolStats(), cfg, tempDir))
+
+	assert.Equal(t, "Token secret", authorization)
+	assert.Contains(t, body, "technology=Golang count=306i")
+
+	entries, err := os.ReadDir(tempDir)
+	require.NoError(t, err)
+	assert.Empty(t, entries, "при отправке на сервер файл не создаётся")
+}
+
+func TestSaveLineProtocol_PushError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	cfg := config.LineProtocolConfig{Enabled: true, Measurement: "hh", WriteUrl: server.URL}
+	err := saveLineProtocol(lineProtocolStats(), cfg, t.TempDir())
+
+	assert.ErrorContains(t, err, "status 401")
+}
@@ -0,0 +1,114 @@
+package storage
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"hhparser/internal/hhparser"
+	"math"
+	"strconv"
+	"time"
+)
+
+// RunInfo — сведения о запуске, которые известны только вызывающему коду
+type RunInfo struct {
+	ID         string
+	StartedAt  time.Time
+	FinishedAt time.Time
+	Version    string
+	ConfigHash string
+	Source     string // откуда получены числа, например шаблон URL поиска hh.ru
+
+	// Число одновременных запросов за сбор, nil — неизвестно
+	Concurrency *hhparser.Concurrency
+	// Итог сбора вакансий, nil — не выполнялся
+	Postings *hhparser.PostingsReport
+}
+
+// Manifest описывает происхождение чисел запуска: кто, когда, с каким конфигом
+// и с каким результатом выполнил каждый запрос
+type Manifest struct {
+	RunID      string    `json:"runId"`
+	StartedAt  time.Time `json:"startedAt"`
+	FinishedAt time.Time `json:"finishedAt"`
+	Duration   float64   `json:"durationSeconds"`
+	Version    string    `json:"version"`
+	ConfigHash string    `json:"configHash"`
+	Source     string    `json:"source"`
+
+	Requests     int            `json:"requests"`
+	HTTPStatuses map[string]int `json:"httpStatuses,omitempty"`
+	Failures     int            `json:"failures"`
+	Empty        int            `json:"empty"`
+	Blocks       int            `json:"blocks"` // ответов-блокировок: капча и 429
+
+	Concurrency *hhparser.Concurrency    `json:"concurrency,omitempty"`
+	Postings    *hhparser.PostingsReport `json:"vacancies,omitempty"`
+	Queries     []QueryResult            `json:"queries"`
+}
+
+// QueryResult — итог запроса одной технологии в одном городе
+type QueryResult struct {
+	Technology   string `json:"technology"`
+	City         int    `json:"city"`
+	Status       string `json:"status"`
+	Attempts     int    `json:"attempts"`
+	Blocks       int    `json:"blocks,omitempty"`
+	HTTPStatuses []int  `json:"httpStatuses,omitempty"`
+	Count        int    `json:"count"`
+	Error        string `json:"error,omitempty"`
+}
+
+// NewRunID возвращает идентификатор запуска вида 20260215T090000-1a2b3c
+func NewRunID(startedAt time.Time) string {
+	suffix := make([]byte, 3)
+	_, _ = rand.Read(suffix)
+	return startedAt.UTC().Format("20060102T150405") + "-" + hex.EncodeToString(suffix)
+}
+
+func newManifest(run RunInfo, vacancies []*hhparser.Vacancy) *Manifest {
+	manifest := &Manifest{
+		RunID:        run.ID,
+		StartedAt:    run.StartedAt,
+		FinishedAt:   run.FinishedAt,
+		Duration:     math.Round(run.FinishedAt.Sub(run.StartedAt).Seconds()*1000) / 1000,
+		Version:      run.Version,
+		ConfigHash:   run.ConfigHash,
+		Source:       run.Source,
+		Concurrency:  run.Concurrency,
+		Postings:     run.Postings,
+		HTTPStatuses: make(map[string]int),
+		Queries:      make([]QueryResult, 0, len(vacancies)),
+	}
+
+	for _, vacancy := range vacancies {
+		query := QueryResult{
+			Technology:   vacancy.Key,
+			City:         vacancy.NumCity,
+			Status:       vacancy.Status,
+			Attempts:     vacancy.Attempts,
+			Blocks:       vacancy.Blocks,
+			HTTPStatuses: vacancy.HTTPStatuses,
+			Count:        vacancy.Count,
+		}
+		if vacancy.Err != nil {
+			query.Error = vacancy.Err.Error()
+		}
+
+		switch vacancy.Status {
+		case hhparser.QueryFailed:
+			manifest.Failures++
+		case hhparser.QueryEmpty:
+			manifest.Empty++
+		}
+
+		manifest.Requests += vacancy.Attempts
+		manifest.Blocks += vacancy.Blocks
+		for _, status := range vacancy.HTTPStatuses {
+			manifest.HTTPStatuses[strconv.Itoa(status)]++
+		}
+
+		manifest.Queries = append(manifest.Queries, query)
+	}
+
+	return manifest
+}
@@ -0,0 +1,74 @@
+package storage
+
+import (
+	"bytes"
+	"errors"
+	"hhparser/internal/config"
+	"hhparser/internal/hhparser"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSaveStatistics_Manifest(t *testing.T) {
+	tempDir := t.TempDir()
+	startedAt := time.Date(2026, 2, 15, 9, 0, 0, 0, time.UTC)
+
+	cfg := StorageConfig{
+		Cities:       []config.CityConfig{{Name: "MOSCOW", Code: 1}, {Name: "KRASNODAR", Code: 53}},
+		Technologies: []config.TechnologyConfig{{Name: "Golang"}},
+		DataDir:      tempDir,
+		Run: &RunInfo{
+			ID:         NewRunID(startedAt),
+			StartedAt:  startedAt,
+			FinishedAt: startedAt.Add(90 * time.Second),
+			Version:    "v1.2.3",
+			ConfigHash: "abc",
+			Source:     "https://hh.ru/search/vacancy?text=%s&area=%d",
+		},
+	}
+
+	vacancies := []*hhparser.Vacancy{
+		{Key: "Golang", NumCity: 1, Count: 306, Status: hhparser.QueryOK, Attempts: 2, HTTPStatuses: []int{503, 200}},
+		{Key: "Golang", NumCity: 53, Status: hhparser.QueryFailed, Attempts: 2, HTTPStatuses: []int{503, 503},
+			Err: errors.New("http: Неожиданный статус ответа HH: 503")},
+	}
+
+	stats, err := SaveStatistics(vacancies, cfg)
+	require.NoError(t, err)
+
+	manifest := stats.Manifest
+	require.NotNil(t, manifest)
+	assert.Regexp(t, `^20260215T090000-[0-9a-f]{6}$`, manifest.RunID)
+	assert.Equal(t, 90.0, manifest.Duration)
+	assert.Equal(t, 4, manifest.Requests)
+	assert.Equal(t, map[string]int{"200": 1, "503": 3}, manifest.HTTPStatuses)
+	assert.Equal(t, 1, manifest.Failures)
+	require.Len(t, manifest.Queries, 2)
+	assert.Equal(t, QueryResult{
+		Technology: "Golang", City: 53, Status: hhparser.QueryFailed, Attempts: 2,
+		HTTPStatuses: []int{503, 503}, Error: "http: Неожиданный статус ответа HH: 503",
+	}, manifest.Queries[1])
+
+	loaded, err := LoadLatest(tempDir)
+	require.NoError(t, err)
+	require.NotNil(t, loaded.Manifest)
+	assert.Equal(t, manifest.RunID, loaded.Manifest.RunID)
+	assert.Equal(t, "v1.2.3", loaded.Manifest.Version)
+
+	var txt bytes.Buffer
+	require.NoError(t, WriteTXT(&txt, stats))
+	assert.Contains(t, txt.String(), "ЗАПУСК")
+	assert.Contains(t, txt.String(), "Golang / 53")
+}
+
+func TestCompare_ConfigChanged(t *testing.T) {
+	from := Statistics{Manifest: &Manifest{ConfigHash: "a"}}
+	to := Statistics{Manifest: &Manifest{ConfigHash: "b"}}
+
+	assert.True(t, Compare(from, to).ConfigChanged)
+	assert.False(t, Compare(from, from).ConfigChanged)
+	assert.False(t, Compare(Statistics{}, to).ConfigChanged, "старые запуски без манифеста не сравниваются")
+}
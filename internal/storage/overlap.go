@@ -0,0 +1,143 @@
+package storage
+
+import (
+	"fmt"
+	"hhparser/internal/hhparser"
+	"io"
+)
+
+// UniqueStatistics — вакансии без повторов: одна вакансия, найденная запросами
+// «Java» и «Spring», считается один раз. Считается по собранным вакансиям,
+// поэтому при упоре в vacancies.max_pages числа меньше, чем в Summary.
+type UniqueStatistics struct {
+	Total      int               `json:"total"`
+	Sum        int               `json:"sum"` // сумма по технологиям, с повторами
+	Cities     map[string]int    `json:"cities"`
+	Categories []UniqueCategory  `json:"categories"`
+	Overlap    TechnologyOverlap `json:"overlap"`
+}
+
+// UniqueCategory — вакансии категории без повторов: вакансия учитывается, если её
+// нашла хотя бы одна технология категории
+type UniqueCategory struct {
+	Name   string         `json:"name"`
+	Cities map[string]int `json:"cities"`
+	Total  int            `json:"total"`
+	Sum    int            `json:"sum"`
+}
+
+// TechnologyOverlap — матрица пересечений: Counts[i][j] — вакансий, найденных
+// и технологией i, и технологией j; на диагонали — все вакансии технологии
+type TechnologyOverlap struct {
+	Technologies []string `json:"technologies"`
+	Counts       [][]int  `json:"counts"`
+}
+
+// collectUnique считает вакансии без повторов по городам и категориям
+// и пересечения технологий. Город вакансии — первый по конфигу, где она найдена.
+func collectUnique(postings []*hhparser.Posting, cfg StorageConfig) *UniqueStatistics {
+	cityNames := make(map[int]string, len(cfg.Cities))
+	for _, city := range cfg.Cities {
+		cityNames[city.Code] = city.Name
+	}
+
+	index := make(map[string]int, len(cfg.Technologies))
+	categoryOf := make(map[string]int, len(cfg.Technologies))
+	categoryIndex := make(map[string]int)
+
+	unique := &UniqueStatistics{
+		Cities: make(map[string]int),
+		Overlap: TechnologyOverlap{
+			Technologies: make([]string, 0, len(cfg.Technologies)),
+			Counts:       make([][]int, len(cfg.Technologies)),
+		},
+	}
+	for i, tech := range cfg.Technologies {
+		key := tech.Key()
+		index[key] = i
+		unique.Overlap.Technologies = append(unique.Overlap.Technologies, key)
+		unique.Overlap.Counts[i] = make([]int, len(cfg.Technologies))
+
+		name := tech.Category
+		if name == "" {
+			name = uncategorized
+		}
+		c, ok := categoryIndex[name]
+		if !ok {
+			c = len(unique.Categories)
+			categoryIndex[name] = c
+			unique.Categories = append(unique.Categories, UniqueCategory{Name: name, Cities: make(map[string]int)})
+		}
+		categoryOf[key] = c
+	}
+
+	for _, posting := range postings {
+		city := cityNames[posting.City]
+		unique.Total++
+		unique.Cities[city]++
+
+		var found []int
+		categories := make(map[int]bool)
+		for _, key := range posting.Technologies {
+			i, ok := index[key]
+			if !ok {
+				continue
+			}
+			found = append(found, i)
+			unique.Sum++
+
+			c := categoryOf[key]
+			unique.Categories[c].Sum++
+			if !categories[c] {
+				categories[c] = true
+				unique.Categories[c].Total++
+				unique.Categories[c].Cities[city]++
+			}
+		}
+
+		for _, i := range found {
+			for _, j := range found {
+				unique.Overlap.Counts[i][j]++
+			}
+		}
+	}
+
+	return unique
+}
+
+func writeUniqueTXT(w io.Writer, stats Statistics) {
+	unique := stats.Unique
+	if unique == nil {
+		return
+	}
+
+	fmt.Fprintf(w, "\nУНИКАЛЬНЫЕ ВАКАНСИИ (%d, с повторами %d)\n", unique.Total, unique.Sum)
+	fmt.Fprint(w, "Категория\t")
+	for _, city := range stats.Cities {
+		fmt.Fprintf(w, "%s\t", city.Name)
+	}
+	fmt.Fprintln(w, "ВСЕГО\tС ПОВТОРАМИ")
+
+	for _, category := range unique.Categories {
+		fmt.Fprintf(w, "%s\t", category.Name)
+		for _, city := range stats.Cities {
+			fmt.Fprintf(w, "%d\t", category.Cities[city.Name])
+		}
+		fmt.Fprintf(w, "%d\t%d\n", category.Total, category.Sum)
+	}
+
+	overlap := unique.Overlap
+	fmt.Fprintf(w, "\nПЕРЕСЕЧЕНИЯ ТЕХНОЛОГИЙ\n")
+	fmt.Fprint(w, "Технология\t")
+	for _, key := range overlap.Technologies {
+		fmt.Fprintf(w, "%s\t", key)
+	}
+	fmt.Fprintln(w)
+	for i, key := range overlap.Technologies {
+		fmt.Fprintf(w, "%s\t", key)
+		for _, count := range overlap.Counts[i] {
+			fmt.Fprintf(w, "%d\t", count)
+		}
+		fmt.Fprintln(w)
+	}
+}
@@ -0,0 +1,80 @@
+package storage
+
+import (
+	"bytes"
+	"hhparser/internal/config"
+	"hhparser/internal/hhparser"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func overlapConfig() StorageConfig {
+	return StorageConfig{
+		Cities: []config.CityConfig{{Name: "MOSCOW", Code: 1}, {Name: "KRASNODAR", Code: 53}},
+		Technologies: []config.TechnologyConfig{
+			{Name: "Java", Search: "java", Category: "languages"},
+			{Name: "Spring", Search: "spring", Category: "frameworks"},
+			{Name: "Javascript", Search: "javascript", Category: "languages"},
+			{Name: "Node.js", Search: "node.js", Category: "frameworks"},
+			{Name: "Kafka", Search: "kafka"},
+		},
+	}
+}
+
+func overlapPostings() []*hhparser.Posting {
+	posting := func(city int, technologies ...string) *hhparser.Posting {
+		return &hhparser.Posting{City: city, Technologies: technologies}
+	}
+	return []*hhparser.Posting{
+		posting(1, "Java", "Spring"),
+		posting(1, "Java"),
+		posting(1, "Javascript", "Node.js"),
+		posting(53, "Java", "Javascript"),
+		posting(53, "Kafka"),
+	}
+}
+
+func TestCollectUnique(t *testing.T) {
+	unique := collectUnique(overlapPostings(), overlapConfig())
+
+	assert.Equal(t, 5, unique.Total)
+	assert.Equal(t, 8, unique.Sum)
+	assert.Equal(t, map[string]int{"MOSCOW": 3, "KRASNODAR": 2}, unique.Cities)
+
+	assert.Equal(t, []UniqueCategory{
+		{Name: "languages", Cities: map[string]int{"MOSCOW": 3, "KRASNODAR": 1}, Total: 4, Sum: 5},
+		{Name: "frameworks", Cities: map[string]int{"MOSCOW": 2}, Total: 2, Sum: 2},
+		{Name: uncategorized, Cities: map[string]int{"KRASNODAR": 1}, Total: 1, Sum: 1},
+	}, unique.Categories, "вакансия Java + Javascript считается в languages один раз")
+
+	assert.Equal(t, TechnologyOverlap{
+		Technologies: []string{"Java", "Spring", "Javascript", "Node.js", "Kafka"},
+		Counts: [][]int{
+			{3, 1, 1, 0, 0},
+			{1, 1, 0, 0, 0},
+			{1, 0, 2, 1, 0},
+			{0, 0, 1, 1, 0},
+			{0, 0, 0, 0, 1},
+		},
+	}, unique.Overlap)
+}
+
+func TestWriteUniqueTXT(t *testing.T) {
+	cfg := overlapConfig()
+	stats := Statistics{
+		Cities: []CityStatistics{{Name: "MOSCOW", Code: 1}, {Name: "KRASNODAR", Code: 53}},
+		Unique: collectUnique(overlapPostings(), cfg),
+	}
+
+	var out bytes.Buffer
+	writeUniqueTXT(&out, stats)
+
+	assert.Contains(t, out.String(), "УНИКАЛЬНЫЕ ВАКАНСИИ (5, с повторами 8)")
+	assert.Contains(t, out.String(), "languages\t3\t1\t4\t5\n")
+	assert.Contains(t, out.String(), "Java\t3\t1\t1\t0\t0\t\n")
+
+	out.Reset()
+	writeUniqueTXT(&out, Statistics{})
+	assert.Empty(t, out.String(), "без сбора вакансий секции нет")
+}
@@ -0,0 +1,56 @@
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"hhparser/internal/hhparser"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// PostingsDir — поддиректория output.directory со снимками вакансий
+const PostingsDir = "vacancies"
+
+// PostingsSnapshot — вакансии, собранные одним запуском
+type PostingsSnapshot struct {
+	Date     time.Time           `json:"date"`
+	RunID    string              `json:"runId,omitempty"`
+	Postings []*hhparser.Posting `json:"vacancies"`
+}
+
+func savePostings(snapshot PostingsSnapshot, directory string) error {
+	dir := filepath.Join(directory, PostingsDir)
+	if err := ensureDir(dir); err != nil {
+		return err
+	}
+
+	file, err := os.Create(filepath.Join(dir, snapshot.Date.Format(dateLayout)+".json"))
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	encoder := json.NewEncoder(file)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(snapshot)
+}
+
+// LoadPostings читает снимок вакансий за дату
+func LoadPostings(directory string, date time.Time) (PostingsSnapshot, error) {
+	var snapshot PostingsSnapshot
+
+	data, err := os.ReadFile(filepath.Join(directory, PostingsDir, date.Format(dateLayout)+".json"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return snapshot, fmt.Errorf("%w: %s", ErrRunNotFound, date.Format(dateLayout))
+		}
+		return snapshot, err
+	}
+
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return snapshot, fmt.Errorf("failed to parse vacancies %s: %w", date.Format(dateLayout), err)
+	}
+
+	return snapshot, nil
+}
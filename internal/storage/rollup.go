@@ -0,0 +1,118 @@
+package storage
+
+import (
+	"hhparser/internal/config"
+	"math"
+)
+
+const uncategorized = "other"
+
+type GroupStatistics struct {
+	Name      string         `json:"name"`
+	Cities    []string       `json:"cities"`
+	Vacancies map[string]int `json:"vacancies"`
+	Total     int            `json:"total"`
+	Share     float64        `json:"share"`
+}
+
+type CategoryStatistics struct {
+	Name         string         `json:"name"`
+	Technologies []string       `json:"technologies"`
+	Cities       map[string]int `json:"cities"`
+	Total        int            `json:"total"`
+	Share        float64        `json:"share"`
+}
+
+// collectRollups считает доли технологий и городов, суммы по группам городов
+// и по категориям технологий. Ожидает заполненные Cities, Summary и Total.
+func collectRollups(stats *Statistics, groups []config.GroupConfig) {
+	stats.Shares = make(map[string]float64, len(stats.Summary))
+	for key, count := range stats.Summary {
+		stats.Shares[key] = percent(count, stats.Total)
+	}
+
+	for i := range stats.Cities {
+		stats.Cities[i].Share = percent(stats.Cities[i].Total, stats.Total)
+	}
+
+	stats.Groups = collectGroups(stats, groups)
+	stats.Categories = collectCategories(stats)
+}
+
+func collectGroups(stats *Statistics, groups []config.GroupConfig) []GroupStatistics {
+	cities := make(map[string]CityStatistics, len(stats.Cities))
+	for _, city := range stats.Cities {
+		cities[city.Name] = city
+	}
+
+	result := make([]GroupStatistics, 0, len(groups))
+	for _, group := range groups {
+		groupStat := GroupStatistics{
+			Name:      group.Name,
+			Vacancies: make(map[string]int),
+		}
+
+		for _, name := range group.Cities {
+			// Отключенные города в группу не попадают
+			city, ok := cities[name]
+			if !ok {
+				continue
+			}
+
+			groupStat.Cities = append(groupStat.Cities, name)
+			for key, count := range city.Vacancies {
+				groupStat.Vacancies[key] += count
+			}
+			groupStat.Total += city.Total
+		}
+
+		groupStat.Share = percent(groupStat.Total, stats.Total)
+		result = append(result, groupStat)
+	}
+
+	return result
+}
+
+func collectCategories(stats *Statistics) []CategoryStatistics {
+	var result []CategoryStatistics
+	index := make(map[string]int)
+
+	for _, tech := range stats.Technologies {
+		name := tech.Category
+		if name == "" {
+			name = uncategorized
+		}
+
+		i, ok := index[name]
+		if !ok {
+			i = len(result)
+			index[name] = i
+			result = append(result, CategoryStatistics{
+				Name:   name,
+				Cities: make(map[string]int),
+			})
+		}
+
+		key := tech.Key()
+		category := &result[i]
+		category.Technologies = append(category.Technologies, key)
+		for _, city := range stats.Cities {
+			category.Cities[city.Name] += city.Vacancies[key]
+		}
+		category.Total += stats.Summary[key]
+	}
+
+	for i := range result {
+		result[i].Share = percent(result[i].Total, stats.Total)
+	}
+
+	return result
+}
+
+// percent возвращает долю part от total в процентах с точностью до сотых
+func percent(part, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return math.Round(float64(part)*10000/float64(total)) / 100
+}
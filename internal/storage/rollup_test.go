@@ -0,0 +1,108 @@
+package storage
+
+import (
+	"hhparser/internal/config"
+	"hhparser/internal/hhparser"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func rollupConfig() StorageConfig {
+	return StorageConfig{
+		Cities: []config.CityConfig{
+			{Name: "MOSCOW", Code: 1},
+			{Name: "KRASNODAR", Code: 53},
+			{Name: "SOCHI", Code: 237},
+		},
+		Technologies: []config.TechnologyConfig{
+			{Name: "Golang", Category: "languages"},
+			{Name: "Python", Category: "languages"},
+			{Name: "Django", Category: "framework"},
+			{Name: "Devops"},
+		},
+		Groups: []config.GroupConfig{
+			{Name: "South", Cities: []string{"KRASNODAR", "SOCHI", "ROSTOV"}},
+		},
+	}
+}
+
+func rollupVacancies() []*hhparser.Vacancy {
+	counts := map[int]map[string]int{
+		1:   {"Golang": 400, "Python": 300, "Django": 200, "Devops": 100},
+		53:  {"Golang": 10, "Python": 40, "Django": 30, "Devops": 20},
+		237: {"Golang": 5, "Python": 15, "Django": 0, "Devops": 0},
+	}
+
+	var vacancies []*hhparser.Vacancy
+	for code, techs := range counts {
+		for name, count := range techs {
+			vacancies = append(vacancies, &hhparser.Vacancy{Name: name, Key: name, NumCity: code, Count: count})
+		}
+	}
+	return vacancies
+}
+
+func TestCollectStatistics_Rollups(t *testing.T) {
+	stats := collectStatistics(rollupVacancies(), rollupConfig())
+
+	assert.Equal(t, 1120, stats.Total)
+	assert.Equal(t, 37.05, stats.Shares["Golang"])
+	assert.Equal(t, 89.29, stats.Cities[0].Share)
+
+	require.Len(t, stats.Groups, 1)
+	south := stats.Groups[0]
+	assert.Equal(t, []string{"KRASNODAR", "SOCHI"}, south.Cities, "ROSTOV не собирался и в группу не входит")
+	assert.Equal(t, 15, south.Vacancies["Golang"])
+	assert.Equal(t, 120, south.Total)
+	assert.Equal(t, 10.71, south.Share)
+
+	require.Len(t, stats.Categories, 3)
+	languages := stats.Categories[0]
+	assert.Equal(t, "languages", languages.Name)
+	assert.Equal(t, []string{"Golang", "Python"}, languages.Technologies)
+	assert.Equal(t, 700, languages.Cities["MOSCOW"])
+	assert.Equal(t, 770, languages.Total)
+	assert.Equal(t, 68.75, languages.Share)
+
+	assert.Equal(t, "framework", stats.Categories[1].Name)
+	assert.Equal(t, uncategorized, stats.Categories[2].Name)
+	assert.Equal(t, 120, stats.Categories[2].Total)
+}
+
+func TestCollectStatistics_EmptyTotal(t *testing.T) {
+	cfg := rollupConfig()
+	vacancies := rollupVacancies()
+	for _, vacancy := range vacancies {
+		vacancy.Count = 0
+	}
+
+	stats := collectStatistics(vacancies, cfg)
+
+	assert.Equal(t, 0, stats.Total)
+	assert.Equal(t, 0.0, stats.Shares["Golang"])
+	assert.Equal(t, 0.0, stats.Groups[0].Share)
+}
+
+func TestSaveTXT_Rollups(t *testing.T) {
+	tempDir := t.TempDir()
+
+	stats := collectStatistics(rollupVacancies(), rollupConfig())
+	stats.Date = time.Date(2026, 2, 14, 0, 0, 0, 0, time.UTC)
+
+	require.NoError(t, saveTXT(stats, tempDir))
+
+	data, err := os.ReadFile(filepath.Join(tempDir, "stats_2026-02-14.txt"))
+	require.NoError(t, err)
+	content := string(data)
+
+	assert.Contains(t, content, "ДОЛЯ ОТ ОБЩЕГО ЧИСЛА (1120)")
+	assert.Contains(t, content, "ГРУППЫ ГОРОДОВ")
+	assert.Contains(t, content, "КАТЕГОРИИ")
+	assert.Regexp(t, `languages\s+700\s+50\s+20\s+770\s+68\.75`, content)
+	assert.Regexp(t, `%\s+10\.71`, content)
+}
@@ -0,0 +1,247 @@
+package storage
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"hhparser/internal/config"
+	"hhparser/internal/hhparser"
+	"io"
+	"math"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SkillsDir — поддиректория output.directory с отчётами по ключевым навыкам
+const SkillsDir = "skills"
+
+// SkillsReport — с какими ключевыми навыками встречается каждая технология
+type SkillsReport struct {
+	Date         time.Time          `json:"date"`
+	RunID        string             `json:"runId,omitempty"`
+	Technologies []TechnologySkills `json:"technologies"`
+}
+
+// TechnologySkills — навыки в вакансиях одной технологии в городе.
+// City пустой и CityCode 0 — по всем городам вместе.
+type TechnologySkills struct {
+	Technology string      `json:"technology"`
+	City       string      `json:"city,omitempty"`
+	CityCode   int         `json:"cityCode,omitempty"`
+	Vacancies  int         `json:"vacancies"` // вакансий технологии с загруженными навыками
+	Skills     []SkillStat `json:"skills"`
+}
+
+type SkillStat struct {
+	Skill     string  `json:"skill"`
+	Count     int     `json:"count"`
+	Frequency float64 `json:"frequency"` // % вакансий технологии с этим навыком
+	// Во сколько раз навык чаще встречается с технологией, чем во всех вакансиях города;
+	// больше 1 — навык характерен именно для технологии
+	Lift float64 `json:"lift"`
+	// Навык совпадает с одной из технологий конфига
+	Configured bool `json:"configured"`
+}
+
+// skillCounter считает вакансии по навыкам в одном наборе вакансий
+type skillCounter struct {
+	total  int
+	counts map[string]int
+}
+
+func newSkillCounter() *skillCounter {
+	return &skillCounter{counts: make(map[string]int)}
+}
+
+func (c *skillCounter) add(skills map[string]bool) {
+	c.total++
+	for skill := range skills {
+		c.counts[skill]++
+	}
+}
+
+// collectSkills строит отчёт по вакансиям с загруженными навыками. Для каждой технологии
+// и города (и по всем городам) навыки отбираются по min_count и сортируются по числу
+// вакансий, в отчёт попадает top первых. База для lift — все собранные вакансии города.
+func collectSkills(postings []*hhparser.Posting, cfg StorageConfig) []TechnologySkills {
+	// Разное написание одного навыка («PostgreSQL», «postgresql») считается вместе,
+	// в отчёт попадает написание, встреченное первым
+	names := make(map[string]string)
+	configured := configuredSkills(cfg.Technologies)
+
+	cityNames := make(map[int]string, len(cfg.Cities))
+	for _, city := range cfg.Cities {
+		cityNames[city.Code] = city.Name
+	}
+
+	type key struct {
+		technology string
+		city       int // 0 — все города
+	}
+	base := make(map[int]*skillCounter)
+	byTechnology := make(map[key]*skillCounter)
+	counter := func(counters map[key]*skillCounter, k key) *skillCounter {
+		if counters[k] == nil {
+			counters[k] = newSkillCounter()
+		}
+		return counters[k]
+	}
+
+	for _, posting := range postings {
+		if !posting.Detailed {
+			continue
+		}
+
+		skills := make(map[string]bool, len(posting.KeySkills))
+		for _, skill := range posting.KeySkills {
+			normalized := strings.ToLower(strings.TrimSpace(skill))
+			if normalized == "" {
+				continue
+			}
+			if _, ok := names[normalized]; !ok {
+				names[normalized] = strings.TrimSpace(skill)
+			}
+			skills[normalized] = true
+		}
+
+		for _, city := range []int{posting.City, 0} {
+			if base[city] == nil {
+				base[city] = newSkillCounter()
+			}
+			base[city].add(skills)
+			for _, technology := range posting.Technologies {
+				counter(byTechnology, key{technology, city}).add(skills)
+			}
+		}
+	}
+
+	var result []TechnologySkills
+	for _, tech := range cfg.Technologies {
+		for _, city := range append(cityCodes(cfg.Cities), 0) {
+			counts := byTechnology[key{tech.Key(), city}]
+			if counts == nil {
+				continue
+			}
+
+			stat := TechnologySkills{
+				Technology: tech.Key(),
+				City:       cityNames[city],
+				CityCode:   city,
+				Vacancies:  counts.total,
+				Skills:     []SkillStat{},
+			}
+			for skill, count := range counts.counts {
+				if count < cfg.Vacancies.SkillsMinCount {
+					continue
+				}
+				frequency := float64(count) / float64(counts.total)
+				baseFrequency := float64(base[city].counts[skill]) / float64(base[city].total)
+				stat.Skills = append(stat.Skills, SkillStat{
+					Skill:      names[skill],
+					Count:      count,
+					Frequency:  percent(count, counts.total),
+					Lift:       math.Round(frequency/baseFrequency*100) / 100,
+					Configured: configured[skill],
+				})
+			}
+
+			slices.SortFunc(stat.Skills, func(a, b SkillStat) int {
+				if a.Count != b.Count {
+					return b.Count - a.Count
+				}
+				return strings.Compare(a.Skill, b.Skill)
+			})
+			if top := cfg.Vacancies.SkillsTop; top > 0 && len(stat.Skills) > top {
+				stat.Skills = stat.Skills[:top]
+			}
+
+			result = append(result, stat)
+		}
+	}
+
+	return result
+}
+
+// configuredSkills — названия и поисковые запросы технологий конфига в нижнем регистре
+func configuredSkills(technologies []config.TechnologyConfig) map[string]bool {
+	result := make(map[string]bool)
+	for _, tech := range technologies {
+		result[strings.ToLower(tech.Name)] = true
+		if search, err := url.QueryUnescape(tech.Search); err == nil {
+			result[strings.ToLower(search)] = true
+		}
+	}
+	return result
+}
+
+func cityCodes(cities []config.CityConfig) []int {
+	codes := make([]int, 0, len(cities))
+	for _, city := range cities {
+		codes = append(codes, city.Code)
+	}
+	return codes
+}
+
+func saveSkills(report SkillsReport, directory string) error {
+	dir := filepath.Join(directory, SkillsDir)
+	if err := ensureDir(dir); err != nil {
+		return err
+	}
+	name := filepath.Join(dir, report.Date.Format(dateLayout))
+
+	jsonFile, err := os.Create(name + ".json")
+	if err != nil {
+		return err
+	}
+	defer jsonFile.Close()
+
+	encoder := json.NewEncoder(jsonFile)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(report); err != nil {
+		return err
+	}
+
+	csvFile, err := os.Create(name + ".csv")
+	if err != nil {
+		return err
+	}
+	defer csvFile.Close()
+
+	return WriteSkillsCSV(csvFile, report)
+}
+
+// WriteSkillsCSV выводит отчёт по навыкам в CSV: строка на пару технология × навык
+// в городе, city пустой — по всем городам
+func WriteSkillsCSV(out io.Writer, report SkillsReport) error {
+	w := csv.NewWriter(out)
+
+	header := []string{"technology", "city", "vacancies", "skill", "count", "frequency", "lift", "configured"}
+	if err := w.Write(header); err != nil {
+		return err
+	}
+
+	for _, tech := range report.Technologies {
+		for _, skill := range tech.Skills {
+			row := []string{
+				tech.Technology,
+				tech.City,
+				strconv.Itoa(tech.Vacancies),
+				skill.Skill,
+				strconv.Itoa(skill.Count),
+				strconv.FormatFloat(skill.Frequency, 'f', -1, 64),
+				strconv.FormatFloat(skill.Lift, 'f', -1, 64),
+				strconv.FormatBool(skill.Configured),
+			}
+			if err := w.Write(row); err != nil {
+				return err
+			}
+		}
+	}
+
+	w.Flush()
+	return w.Error()
+}
@@ -0,0 +1,82 @@
+package storage
+
+import (
+	"bytes"
+	"hhparser/internal/config"
+	"hhparser/internal/hhparser"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func skillsConfig() StorageConfig {
+	return StorageConfig{
+		Cities: []config.CityConfig{{Name: "MOSCOW", Code: 1}, {Name: "KRASNODAR", Code: 53}},
+		Technologies: []config.TechnologyConfig{
+			{Name: "Golang", Search: "golang"},
+			{Name: "Python", Search: "python"},
+		},
+		Vacancies: config.VacanciesConfig{SkillsTop: 2, SkillsMinCount: 2},
+	}
+}
+
+func skillsPostings() []*hhparser.Posting {
+	posting := func(city int, technologies []string, skills ...string) *hhparser.Posting {
+		return &hhparser.Posting{City: city, Technologies: technologies, KeySkills: skills, Detailed: true}
+	}
+	golang, python := []string{"Golang"}, []string{"Python"}
+
+	return []*hhparser.Posting{
+		posting(1, golang, "Go", "Kubernetes", "PostgreSQL"),
+		posting(1, golang, "go", " Kubernetes "),
+		posting(1, python, "Python", "PostgreSQL"),
+		posting(1, []string{"Golang", "Python"}, "Python", "Go", "PostgreSQL"),
+		posting(53, golang, "Go"),
+		{City: 1, Technologies: golang, Detailed: false}, // навыки не загружены
+	}
+}
+
+func TestCollectSkills(t *testing.T) {
+	report := collectSkills(skillsPostings(), skillsConfig())
+
+	require.Len(t, report, 5, "Golang: Москва, Краснодар, все; Python: Москва, все")
+
+	assert.Equal(t, TechnologySkills{
+		Technology: "Golang",
+		City:       "MOSCOW",
+		CityCode:   1,
+		Vacancies:  3,
+		Skills: []SkillStat{
+			{Skill: "Go", Count: 3, Frequency: 100, Lift: 1.33},
+			{Skill: "Kubernetes", Count: 2, Frequency: 66.67, Lift: 1.33},
+		},
+	}, report[0], "PostgreSQL за пределами skills_top, Python — ниже skills_min_count")
+
+	assert.Equal(t, "KRASNODAR", report[1].City)
+	assert.Equal(t, 1, report[1].Vacancies)
+	assert.Empty(t, report[1].Skills)
+
+	all := report[2]
+	assert.Zero(t, all.CityCode)
+	assert.Equal(t, 4, all.Vacancies)
+	assert.Equal(t, SkillStat{Skill: "Go", Count: 4, Frequency: 100, Lift: 1.25}, all.Skills[0])
+
+	python := report[3]
+	assert.Equal(t, "Python", python.Technology)
+	assert.Equal(t, []SkillStat{
+		{Skill: "PostgreSQL", Count: 2, Frequency: 100, Lift: 1.33},
+		{Skill: "Python", Count: 2, Frequency: 100, Lift: 2, Configured: true},
+	}, python.Skills)
+}
+
+func TestWriteSkillsCSV(t *testing.T) {
+	report := SkillsReport{Technologies: collectSkills(skillsPostings(), skillsConfig())[:1]}
+
+	var out bytes.Buffer
+	require.NoError(t, WriteSkillsCSV(&out, report))
+
+	assert.Equal(t, "technology,city,vacancies,skill,count,frequency,lift,configured\n"+
+		"Golang,MOSCOW,3,Go,3,100,1.33,false\n"+
+		"Golang,MOSCOW,3,Kubernetes,2,66.67,1.33,false\n", out.String())
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"hhparser/internal/config"
 	"hhparser/internal/hhparser"
+	"log/slog"
 	"os"
 	"time"
 )
@@ -11,7 +12,16 @@ import (
 type StorageConfig struct {
 	Cities       []config.CityConfig
 	Technologies []config.TechnologyConfig
+	Groups       []config.GroupConfig
 	DataDir      string
+	LineProtocol config.LineProtocolConfig
+	Vacancies    config.VacanciesConfig
+
+	// Если задан, к статистике прикладывается манифест запуска
+	Run *RunInfo
+
+	// Вакансии запуска, nil — сбор вакансий не выполнялся
+	Postings []*hhparser.Posting
 }
 
 type Statistics struct {
@@ -19,6 +29,14 @@ type Statistics struct {
 	Technologies []config.TechnologyConfig `json:"technologiesConfig"`
 	Cities       []CityStatistics          `json:"cities"`
 	Summary      map[string]int            `json:"summary"`
+	Total        int                       `json:"total"`
+	Shares       map[string]float64        `json:"shares,omitempty"`
+	Groups       []GroupStatistics         `json:"groups,omitempty"`
+	Categories   []CategoryStatistics      `json:"categories,omitempty"`
+	Employers    []EmployerStatistics      `json:"employers,omitempty"`
+	Unique       *UniqueStatistics         `json:"unique,omitempty"`
+	Lifecycle    *LifecycleStatistics      `json:"lifecycle,omitempty"`
+	Manifest     *Manifest                 `json:"manifest,omitempty"`
 }
 
 type CityStatistics struct {
@@ -26,32 +44,78 @@ type CityStatistics struct {
 	Code      int            `json:"code"`
 	Vacancies map[string]int `json:"vacancies"`
 	Total     int            `json:"total"`
+	Share     float64        `json:"share,omitempty"`
 }
 
 func NewStorageConfig(cfg *config.Config) StorageConfig {
 	return StorageConfig{
 		Cities:       cfg.Cities,
 		Technologies: cfg.Technologies,
+		Groups:       cfg.Groups,
 		DataDir:      cfg.Output.Directory,
+		LineProtocol: cfg.Output.LineProtocol,
+		Vacancies:    cfg.Vacancies,
 	}
 }
 
-func SaveStatistics(vacancies []*hhparser.Vacancy, cfg StorageConfig) error {
-	stats := collectStatistics(vacancies, cfg)
-
+// SaveStatistics собирает статистику по результатам парсинга, сохраняет её
+// во всех включенных форматах и возвращает сохранённые данные
+func SaveStatistics(vacancies []*hhparser.Vacancy, cfg StorageConfig) (Statistics, error) {
 	if err := ensureDir(cfg.DataDir); err != nil {
-		return err
+		return Statistics{}, err
+	}
+
+	// Реестр обновляется до подсчёта: после инкрементального сбора
+	// статистика вакансий считается по всем активным вакансиям реестра
+	var lifecycle *LifecycleStatistics
+	if cfg.Postings != nil {
+		var err error
+		lifecycle, cfg.Postings, err = updateLifecycle(cfg, time.Now())
+		if err != nil {
+			return Statistics{}, fmt.Errorf("failed to update vacancies registry: %w", err)
+		}
 	}
 
+	stats := collectStatistics(vacancies, cfg)
+	stats.Lifecycle = lifecycle
+
 	if err := saveJSON(stats, cfg.DataDir); err != nil {
-		return err
+		return stats, err
 	}
 
 	if err := saveTXT(stats, cfg.DataDir); err != nil {
-		return err
+		return stats, err
 	}
 
-	return nil
+	if cfg.LineProtocol.Enabled {
+		if err := saveLineProtocol(stats, cfg.LineProtocol, cfg.DataDir); err != nil {
+			return stats, err
+		}
+	}
+
+	if cfg.Postings != nil {
+		snapshot := PostingsSnapshot{Date: stats.Date, RunID: runID(stats), Postings: cfg.Postings}
+		if err := savePostings(snapshot, cfg.DataDir); err != nil {
+			return stats, err
+		}
+	}
+
+	if cfg.Postings != nil && cfg.Vacancies.Details {
+		report := SkillsReport{Date: stats.Date, RunID: runID(stats), Technologies: collectSkills(cfg.Postings, cfg)}
+		if err := saveSkills(report, cfg.DataDir); err != nil {
+			return stats, err
+		}
+	}
+
+	slog.Info("statistics saved",
+		"dir", cfg.DataDir,
+		"run_id", runID(stats),
+		"date", stats.Date.Format("2006-01-02"),
+		"total", stats.Total,
+		"line_protocol", cfg.LineProtocol.Enabled,
+		"vacancies", len(cfg.Postings))
+
+	return stats, nil
 }
 
 func ensureDir(dir string) error {
@@ -84,14 +148,34 @@ func collectStatistics(vacancies []*hhparser.Vacancy, cfg StorageConfig) Statist
 		}
 
 		for _, tech := range cfg.Technologies {
-			count := hhparser.GetkeyWordByNameAndCountry(vacancies, tech.Name, city.Code).Count
-			cityStat.Vacancies[tech.Name] = count
+			key := tech.Key()
+			count := hhparser.GetkeyWordByNameAndCountry(vacancies, key, city.Code).Count
+			cityStat.Vacancies[key] = count
 			cityStat.Total += count
-			stats.Summary[tech.Name] += count
+			stats.Summary[key] += count
 		}
 
 		stats.Cities = append(stats.Cities, cityStat)
+		stats.Total += cityStat.Total
+	}
+
+	collectRollups(&stats, cfg.Groups)
+
+	if cfg.Postings != nil {
+		stats.Employers = collectEmployers(cfg.Postings, cfg)
+		stats.Unique = collectUnique(cfg.Postings, cfg)
+	}
+
+	if cfg.Run != nil {
+		stats.Manifest = newManifest(*cfg.Run, vacancies)
 	}
 
 	return stats
 }
+
+func runID(stats Statistics) string {
+	if stats.Manifest == nil {
+		return ""
+	}
+	return stats.Manifest.RunID
+}
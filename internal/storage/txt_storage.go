@@ -2,6 +2,8 @@ package storage
 
 import (
 	"fmt"
+	"hhparser/internal/hhparser"
+	"io"
 	"os"
 	"text/tabwriter"
 )
@@ -15,7 +17,12 @@ func saveTXT(stats Statistics, directory string) error {
 	}
 	defer file.Close()
 
-	w := tabwriter.NewWriter(file, 0, 0, 2, ' ', 0)
+	return WriteTXT(file, stats)
+}
+
+// WriteTXT выводит статистику текстовой таблицей, как в файле stats_YYYY-MM-DD.txt
+func WriteTXT(out io.Writer, stats Statistics) error {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
 
 	fmt.Fprintf(w, "СТАТИСТИКА ВАКАНСИЙ\n")
 	fmt.Fprintf(w, "Дата: %s\n\n", stats.Date.Format("02.01.2006"))
@@ -33,13 +40,125 @@ func saveTXT(stats Statistics, directory string) error {
 	fmt.Fprintln(w, "----------")
 
 	for _, tech := range stats.Technologies {
-		fmt.Fprintf(w, "%s\t", tech.Name)
+		key := tech.Key()
+		fmt.Fprintf(w, "%s\t", key)
 		for _, city := range stats.Cities {
-			fmt.Fprintf(w, "%d\t", city.Vacancies[tech.Name])
+			fmt.Fprintf(w, "%d\t", city.Vacancies[key])
 		}
-		fmt.Fprintf(w, "%d\t", stats.Summary[tech.Name])
+		fmt.Fprintf(w, "%d\t", stats.Summary[key])
 		fmt.Fprintln(w)
 	}
 
+	writeSharesTXT(w, stats)
+	writeGroupsTXT(w, stats)
+	writeCategoriesTXT(w, stats)
+	writeUniqueTXT(w, stats)
+	writeEmployersTXT(w, stats)
+	writeLifecycleTXT(w, stats)
+	writeManifestTXT(w, stats)
+
 	return w.Flush()
 }
+
+func writeSharesTXT(w io.Writer, stats Statistics) {
+	if len(stats.Shares) == 0 {
+		return
+	}
+
+	fmt.Fprintf(w, "\nДОЛЯ ОТ ОБЩЕГО ЧИСЛА (%d)\n", stats.Total)
+	fmt.Fprintln(w, "Технология\tВСЕГО\t%")
+	for _, tech := range stats.Technologies {
+		key := tech.Key()
+		fmt.Fprintf(w, "%s\t%d\t%.2f\n", key, stats.Summary[key], stats.Shares[key])
+	}
+}
+
+func writeGroupsTXT(w io.Writer, stats Statistics) {
+	if len(stats.Groups) == 0 {
+		return
+	}
+
+	fmt.Fprintf(w, "\nГРУППЫ ГОРОДОВ\n")
+	fmt.Fprint(w, "Технология\t")
+	for _, group := range stats.Groups {
+		fmt.Fprintf(w, "%s\t", group.Name)
+	}
+	fmt.Fprintln(w)
+
+	for _, tech := range stats.Technologies {
+		key := tech.Key()
+		fmt.Fprintf(w, "%s\t", key)
+		for _, group := range stats.Groups {
+			fmt.Fprintf(w, "%d\t", group.Vacancies[key])
+		}
+		fmt.Fprintln(w)
+	}
+
+	fmt.Fprint(w, "ВСЕГО\t")
+	for _, group := range stats.Groups {
+		fmt.Fprintf(w, "%d\t", group.Total)
+	}
+	fmt.Fprintln(w)
+
+	fmt.Fprint(w, "%\t")
+	for _, group := range stats.Groups {
+		fmt.Fprintf(w, "%.2f\t", group.Share)
+	}
+	fmt.Fprintln(w)
+}
+
+func writeCategoriesTXT(w io.Writer, stats Statistics) {
+	if len(stats.Categories) == 0 {
+		return
+	}
+
+	fmt.Fprintf(w, "\nКАТЕГОРИИ\n")
+	fmt.Fprint(w, "Категория\t")
+	for _, city := range stats.Cities {
+		fmt.Fprintf(w, "%s\t", city.Name)
+	}
+	fmt.Fprintln(w, "ВСЕГО\t%")
+
+	for _, category := range stats.Categories {
+		fmt.Fprintf(w, "%s\t", category.Name)
+		for _, city := range stats.Cities {
+			fmt.Fprintf(w, "%d\t", category.Cities[city.Name])
+		}
+		fmt.Fprintf(w, "%d\t%.2f\n", category.Total, category.Share)
+	}
+}
+
+func writeManifestTXT(w io.Writer, stats Statistics) {
+	manifest := stats.Manifest
+	if manifest == nil {
+		return
+	}
+
+	fmt.Fprintf(w, "\nЗАПУСК\n")
+	fmt.Fprintf(w, "ID:\t%s\n", manifest.RunID)
+	fmt.Fprintf(w, "Версия:\t%s\n", manifest.Version)
+	fmt.Fprintf(w, "Конфиг:\t%s\n", manifest.ConfigHash)
+	fmt.Fprintf(w, "Длительность:\t%.1f с\n", manifest.Duration)
+	fmt.Fprintf(w, "Запросов:\t%d\n", manifest.Requests)
+	fmt.Fprintf(w, "Без вакансий:\t%d\n", manifest.Empty)
+	fmt.Fprintf(w, "Ошибок:\t%d\n", manifest.Failures)
+	if c := manifest.Concurrency; c != nil {
+		fmt.Fprintf(w, "Параллельность:\t%d, в среднем %.1f (%d..%d)\n", c.Final, c.Average, c.Min, c.Max)
+	}
+	if p := manifest.Postings; p != nil {
+		fmt.Fprintf(w, "Вакансий:\t%d уникальных из %d, страниц вакансий %d, без запроса %d, ошибок %d\n",
+			p.Unique, p.Found, p.Details, p.Reused, p.Failures)
+		if p.Since != nil {
+			fmt.Fprintf(w, "Вакансии с:\t%s (инкрементальный сбор)\n", p.Since.Format("2006-01-02 15:04"))
+		}
+	}
+	if manifest.Blocks > 0 {
+		fmt.Fprintf(w, "Блокировок:\t%d\n", manifest.Blocks)
+	}
+
+	for _, query := range manifest.Queries {
+		if query.Status == hhparser.QueryFailed {
+			fmt.Fprintf(w, "  %s / %d\t%s\n", query.Technology, query.City, query.Error)
+		}
+	}
+}